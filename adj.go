@@ -404,3 +404,71 @@ func (g LabeledAdjacencyList) WeightedOutDegree(n NI, w WeightFunc) (d float64)
 // of "strength" where loops exist is unclear.  So while I could write an
 // UndirectedWeighted degree function that doubles loops but not edges,
 // I'm going to just leave this for now.
+
+// coreNumbers computes core numbers by the algorithm of Batagelj and
+// Zaversnik.
+//
+// Argument deg gives the initial degree of each node, by whatever measure
+// of degree is being used.  Function nbrs must visit the nodes whose degree
+// counts an arc or edge to n, once for each such arc or edge.  Deg is
+// modified and left holding the core numbers.
+func coreNumbers(deg []int, nbrs func(n NI, visit func(NI))) []int {
+	corePeel(deg, nbrs)
+	return deg
+}
+
+// corePeel removes nodes in order of least remaining degree, as for
+// coreNumbers, in O(n+m) time.
+//
+// Arguments are as for coreNumbers and deg is left holding the core
+// numbers.  Returned is the order in which nodes were removed, a
+// degeneracy ordering with the first node removed first.
+func corePeel(deg []int, nbrs func(n NI, visit func(NI))) []NI {
+	maxDeg := 0
+	for _, d := range deg {
+		if d > maxDeg {
+			maxDeg = d
+		}
+	}
+	// nodes bucket sorted by degree
+	bin := make([]int, maxDeg+1) // start of each bucket in vert
+	for _, d := range deg {
+		bin[d]++
+	}
+	start := 0
+	for d, c := range bin {
+		bin[d] = start
+		start += c
+	}
+	vert := make([]NI, len(deg))
+	pos := make([]int, len(deg))
+	for n, d := range deg {
+		pos[n] = bin[d]
+		vert[pos[n]] = NI(n)
+		bin[d]++
+	}
+	for d := maxDeg; d > 0; d-- {
+		bin[d] = bin[d-1]
+	}
+	if len(bin) > 0 {
+		bin[0] = 0
+	}
+	for _, v := range vert {
+		dv := deg[v]
+		nbrs(v, func(u NI) {
+			du := deg[u]
+			if du <= dv {
+				return
+			}
+			// move u to the start of its bucket, then to the next lower
+			pu, pw := pos[u], bin[du]
+			if w := vert[pw]; w != u {
+				vert[pu], vert[pw] = w, u
+				pos[u], pos[w] = pw, pu
+			}
+			bin[du]++
+			deg[u]--
+		})
+	}
+	return vert
+}
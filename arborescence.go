@@ -0,0 +1,223 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "github.com/soniakeys/bits"
+
+// arborescence.go has the Chu-Liu/Edmonds algorithm for minimum spanning
+// arborescences, as improved by Tarjan to O(m log n) time.
+//
+// Cheapest incoming arcs are found with a meldable heap (a skew heap with
+// lazy weight adjustment) for each node, and cycles are contracted with a
+// union-find structure supporting rollback, so the arborescence can be
+// recovered by expanding cycles in reverse order of contraction.
+
+// edArc is an arc considered by Edmonds.
+type edArc struct {
+	from, to NI
+	label    LI
+	w        float64
+}
+
+// edHeap is a skew heap of arcs with lazy weight adjustment.  Heap nodes
+// are indexes into edHeap.nodes; -1 is an empty heap.
+type edHeap struct {
+	arcs  []edArc
+	nodes []edHeapNode
+}
+
+type edHeapNode struct {
+	arc   int     // index into arcs
+	w     float64 // current (reduced) weight of the arc
+	delta float64 // pending adjustment to weights of children
+	l, r  int
+}
+
+func (h *edHeap) prop(x int) {
+	nd := &h.nodes[x]
+	if nd.delta != 0 {
+		if nd.l >= 0 {
+			h.nodes[nd.l].w += nd.delta
+			h.nodes[nd.l].delta += nd.delta
+		}
+		if nd.r >= 0 {
+			h.nodes[nd.r].w += nd.delta
+			h.nodes[nd.r].delta += nd.delta
+		}
+		nd.delta = 0
+	}
+}
+
+func (h *edHeap) merge(a, b int) int {
+	if a < 0 {
+		return b
+	}
+	if b < 0 {
+		return a
+	}
+	if h.nodes[b].w < h.nodes[a].w {
+		a, b = b, a
+	}
+	h.prop(a)
+	na := &h.nodes[a]
+	na.l, na.r = h.merge(b, na.r), na.l
+	return a
+}
+
+// add adjusts the weights of all arcs in heap x.
+func (h *edHeap) add(x int, d float64) {
+	h.nodes[x].w += d
+	h.nodes[x].delta += d
+}
+
+func (h *edHeap) pop(x int) int {
+	h.prop(x)
+	return h.merge(h.nodes[x].l, h.nodes[x].r)
+}
+
+// Edmonds implements the Chu-Liu/Edmonds algorithm for constructing a
+// minimum spanning arborescence on a directed graph.
+//
+// An arborescence is a directed tree where all arcs lead away from the root.
+// A minimum spanning arborescence from root is one that reaches all nodes
+// reachable from root and has minimum total weight.  This implementation
+// follows Tarjan and takes O(m log n) time.  Arc weights may be negative.
+//
+// The arborescence is returned as a FromList with Len, Leaves, and MaxLen
+// populated.  Argument labels holds the label of the arc leading to each
+// node.  Also returned is the total weight of the arborescence.
+//
+// If some nodes are not reachable from root then no spanning arborescence
+// exists.  In this case Edmonds returns a minimum arborescence spanning the
+// nodes that are reachable and returns the remaining nodes as unreachable.
+// Unreachable nodes have From = -1 and Len = 0 in the returned FromList.
+// If all nodes are reachable, unreachable is nil.
+func (g LabeledDirected) Edmonds(root NI, w WeightFunc) (f FromList, labels []LI, dist float64, unreachable []NI) {
+	a := g.LabeledAdjacencyList
+	f = NewFromList(len(a))
+	labels = make([]LI, len(a))
+	// reachable nodes
+	var sp FromList
+	g.Unlabeled().SpanTree(root, &sp)
+	for n, e := range sp.Paths {
+		if e.Len == 0 {
+			unreachable = append(unreachable, NI(n))
+		}
+	}
+	// a heap of incoming arcs for each node
+	h := &edHeap{}
+	heap := make([]int, len(a))
+	for n := range heap {
+		heap[n] = -1
+	}
+	for fr, to := range a {
+		if sp.Paths[fr].Len == 0 {
+			continue
+		}
+		for _, to := range to {
+			if to.To == root || to.To == NI(fr) {
+				continue
+			}
+			x := len(h.arcs)
+			wt := w(to.Label)
+			h.arcs = append(h.arcs, edArc{NI(fr), to.To, to.Label, wt})
+			h.nodes = append(h.nodes, edHeapNode{arc: x, w: wt, l: -1, r: -1})
+			heap[to.To] = h.merge(heap[to.To], x)
+		}
+	}
+	ds := NewRollbackUnionFind(len(a))
+	seen := make([]NI, len(a))
+	for n := range seen {
+		seen[n] = -1
+	}
+	seen[root] = root
+	in := make([]int, len(a)) // arc chosen into each node or super node
+	q := make([]int, len(a))  // arcs chosen on the current search path
+	path := make([]NI, len(a))
+	type cycle struct {
+		u    NI    // super node
+		t    int   // union count before contraction
+		arcs []int // arcs chosen within the cycle
+	}
+	var cycles []cycle
+	for s := range a {
+		if sp.Paths[s].Len == 0 {
+			continue
+		}
+		u := NI(s)
+		qi := 0
+		for seen[u] < 0 {
+			x := heap[u]
+			arc := h.nodes[x].arc
+			dist += h.nodes[x].w
+			h.add(x, -h.nodes[x].w)
+			heap[u] = h.pop(x)
+			q[qi] = arc
+			path[qi] = u
+			qi++
+			seen[u] = NI(s)
+			u = ds.Find(h.arcs[arc].from)
+			if seen[u] == NI(s) {
+				// found a cycle, contract it
+				c := -1
+				end := qi
+				t := ds.Unions()
+				for {
+					qi--
+					v := path[qi]
+					c = h.merge(c, heap[v])
+					if !ds.Union(u, v) {
+						break
+					}
+				}
+				u = ds.Find(u)
+				heap[u] = c
+				seen[u] = -1
+				cycles = append(cycles, cycle{u, t, append([]int{}, q[qi:end]...)})
+			}
+		}
+		for _, arc := range q[:qi] {
+			in[ds.Find(h.arcs[arc].to)] = arc
+		}
+	}
+	// expand cycles in reverse order of contraction
+	for i := len(cycles) - 1; i >= 0; i-- {
+		c := cycles[i]
+		ds.Rollback(c.t)
+		inArc := in[c.u]
+		for _, arc := range c.arcs {
+			in[ds.Find(h.arcs[arc].to)] = arc
+		}
+		in[ds.Find(h.arcs[inArc].to)] = inArc
+	}
+	p := f.Paths
+	for n := range p {
+		if n == int(root) || sp.Paths[n].Len == 0 {
+			p[n].From = -1
+			continue
+		}
+		arc := h.arcs[in[n]]
+		p[n].From = arc.from
+		labels[n] = arc.label
+	}
+	// Len, Leaves, MaxLen
+	d := depths(p)
+	f.Leaves = bits.New(len(p))
+	for n := range p {
+		if sp.Paths[n].Len == 0 {
+			continue
+		}
+		p[n].Len = d[n] + 1
+		if p[n].Len > f.MaxLen {
+			f.MaxLen = p[n].Len
+		}
+		f.Leaves.SetBit(n, 1)
+	}
+	for _, e := range p {
+		if e.From >= 0 {
+			f.Leaves.SetBit(int(e.From), 0)
+		}
+	}
+	return
+}
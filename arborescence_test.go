@@ -0,0 +1,152 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledDirected_Edmonds() {
+	//        0
+	//   (10)/ \(2)
+	//      v   v   (1)
+	//     1<----2------>4
+	//     | (1) ^
+	//  (2)|     |(4)
+	//     v     |
+	//     3-----/
+	//
+	// 5  (unreachable)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 10}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 2}},
+		2: {{To: 1, Label: 1}, {To: 4, Label: 1}},
+		3: {{To: 2, Label: 4}},
+		5: {},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	f, labels, dist, unreachable := g.Edmonds(0, w)
+	fmt.Println("n  from  label")
+	for n, e := range f.Paths {
+		if e.Len > 0 {
+			fmt.Printf("%d  %4d  %5d\n", n, e.From, labels[n])
+		}
+	}
+	fmt.Println("total weight:", dist)
+	fmt.Println("unreachable:", unreachable)
+	// Output:
+	// n  from  label
+	// 0    -1      0
+	// 1     2      1
+	// 2     0      2
+	// 3     1      2
+	// 4     2      1
+	// total weight: 6
+	// unreachable: [5]
+}
+
+func TestEdmonds(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 300; i++ {
+		n := 1 + r.Intn(6)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		for m := r.Intn(n * n); m > 0; m-- {
+			fr := graph.NI(r.Intn(n))
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr],
+				graph.Half{To: graph.NI(r.Intn(n)), Label: graph.LI(r.Intn(21) - 5)})
+		}
+		root := graph.NI(r.Intn(n))
+		f, labels, dist, unreachable := g.Edmonds(root, w)
+		want, reach := edmondsBF(g, root, w)
+		if len(unreachable) != n-reach {
+			t.Fatal("unreachable", unreachable, reach)
+		}
+		if dist != want {
+			t.Fatal("dist", dist, want)
+		}
+		// f must be an arborescence of arcs of g with weight dist
+		if ok, _ := f.Cyclic(); ok {
+			t.Fatal("cyclic")
+		}
+		sum := 0.
+		for n, e := range f.Paths {
+			if e.From < 0 {
+				continue
+			}
+			if f.Root(graph.NI(n)) != root {
+				t.Fatal("root")
+			}
+			if ok, _ := g.HasArcLabel(e.From, graph.NI(n), labels[n]); !ok {
+				t.Fatal("arc not in g")
+			}
+			sum += w(labels[n])
+		}
+		if sum != dist {
+			t.Fatal("sum", sum, dist)
+		}
+	}
+}
+
+// edmondsBF finds the minimum arborescence weight by trying all
+// combinations of incoming arcs.  Also returned is the number of nodes
+// reachable from root.
+func edmondsBF(g graph.LabeledDirected, root graph.NI, w graph.WeightFunc) (float64, int) {
+	a := g.LabeledAdjacencyList
+	var sp graph.FromList
+	reach, _ := g.Unlabeled().SpanTree(root, &sp)
+	type arc struct {
+		from graph.NI
+		wt   float64
+	}
+	in := make([][]arc, len(a))
+	for fr, to := range a {
+		if sp.Paths[fr].Len == 0 {
+			continue
+		}
+		for _, h := range to {
+			if h.To != root && h.To != graph.NI(fr) {
+				in[h.To] = append(in[h.To], arc{graph.NI(fr), w(h.Label)})
+			}
+		}
+	}
+	best := math.Inf(1)
+	from := make([]graph.NI, len(a))
+	var try func(n int, sum float64)
+	try = func(n int, sum float64) {
+		if n == len(a) {
+			// check all nodes lead to root
+			for m := range a {
+				x, steps := graph.NI(m), 0
+				for x != root && x >= 0 && steps <= len(a) {
+					x = from[x]
+					steps++
+				}
+				if x < 0 && sp.Paths[m].Len > 0 || steps > len(a) {
+					return
+				}
+			}
+			if sum < best {
+				best = sum
+			}
+			return
+		}
+		if graph.NI(n) == root || sp.Paths[n].Len == 0 {
+			from[n] = -1
+			try(n+1, sum)
+			return
+		}
+		for _, c := range in[n] {
+			from[n] = c.from
+			try(n+1, sum+c.wt)
+		}
+	}
+	try(0, 0)
+	return best, reach
+}
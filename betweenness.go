@@ -0,0 +1,398 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"runtime"
+)
+
+// betweenness.go has Brandes' algorithm for betweenness centrality.
+//
+// Betweenness of a node is the sum over pairs of other nodes s, t of the
+// fraction of shortest paths from s to t that pass through the node.
+// Betweenness of an arc or edge is similarly the sum over all pairs of the
+// fraction of shortest paths using the arc or edge.
+//
+// The algorithm does a single source shortest path search from each node
+// and then accumulates path dependencies in reverse order of distance.
+// Time is O(nm) for unweighted graphs and O(nm + n² log n) for weighted
+// graphs.
+
+// BetweennessOptions holds options for betweenness centrality.
+//
+// A nil *BetweennessOptions is equivalent to a pointer to the zero value,
+// requesting exact, unnormalized results.
+type BetweennessOptions struct {
+	// Normalize scales results by the number of node pairs that can
+	// contribute, giving values from 0 to 1 for simple graphs.
+	//
+	// Node results are divided by (n-1)(n-2) for directed graphs and by
+	// (n-1)(n-2)/2 for undirected graphs.  Arc or edge results are divided
+	// by n(n-1) for directed graphs and n(n-1)/2 for undirected graphs.
+	Normalize bool
+
+	// Samples, if greater than zero and less than the order of the graph,
+	// approximates betweenness by searching from only Samples randomly
+	// chosen distinct source nodes.  Results are then scaled by n/Samples
+	// to estimate exact values.
+	Samples int
+
+	// Rand is the random source used for choosing samples.  If nil, the
+	// rand package default shared source is used.
+	Rand *rand.Rand
+
+	// Workers is the number of goroutines to divide source nodes among.
+	// If less than 1, runtime.GOMAXPROCS(0) is used.
+	Workers int
+}
+
+// bcWorker holds results accumulated by a single worker and working
+// storage for single source searches.
+type bcWorker struct {
+	node  []float64
+	arc   [][]float64
+	order []NI // nodes in order of nondecreasing distance from source
+	sigma []float64
+	delta []float64
+	dist  []int        // for BFS
+	r     []tentResult // for Dijkstra
+}
+
+// betweenness runs single source accumulation function sa from the source
+// nodes requested by opt, divided among workers, and sums and scales the
+// results.
+//
+// Argument arcLen gives the number of arcs from a node.  Edge results for
+// undirected graphs are not halved here; see bcEdges.
+func betweenness(order int, arcLen func(NI) int, opt *BetweennessOptions, undirected bool, sa func(*bcWorker, NI)) (node []float64, arc [][]float64) {
+	var o BetweennessOptions
+	if opt != nil {
+		o = *opt
+	}
+	sources := make([]NI, order)
+	for n := range sources {
+		sources[n] = NI(n)
+	}
+	scale := 1.
+	if o.Samples > 0 && o.Samples < order {
+		p := rand.Perm
+		if o.Rand != nil {
+			p = o.Rand.Perm
+		}
+		for i, n := range p(order)[:o.Samples] {
+			sources[i] = NI(n)
+		}
+		sources = sources[:o.Samples]
+		scale = float64(order) / float64(o.Samples)
+	}
+	nw := o.Workers
+	if nw < 1 {
+		nw = runtime.GOMAXPROCS(0)
+	}
+	if nw > len(sources) {
+		nw = len(sources)
+	}
+	if nw < 1 {
+		nw = 1
+	}
+	ws := make([]bcWorker, nw)
+	for i := range ws {
+		w := &ws[i]
+		w.node = make([]float64, order)
+		w.arc = make([][]float64, order)
+		for n := range w.arc {
+			w.arc[n] = make([]float64, arcLen(NI(n)))
+		}
+		w.sigma = make([]float64, order)
+		w.delta = make([]float64, order)
+	}
+	parallelRange(nw, len(sources), func(wk, lo, hi int) {
+		for _, s := range sources[lo:hi] {
+			sa(&ws[wk], s)
+		}
+	})
+	node = ws[0].node
+	arc = ws[0].arc
+	for _, w := range ws[1:] {
+		for n, c := range w.node {
+			node[n] += c
+		}
+		for n, to := range w.arc {
+			for x, c := range to {
+				arc[n][x] += c
+			}
+		}
+	}
+	ns, es := scale, scale
+	if undirected {
+		ns /= 2
+	}
+	if o.Normalize {
+		n := float64(order)
+		pairs := 1.
+		if undirected {
+			pairs = 2
+		}
+		if order > 2 {
+			ns *= pairs / ((n - 1) * (n - 2))
+		}
+		if order > 1 {
+			es *= pairs / (n * (n - 1))
+		}
+	}
+	if ns != 1 {
+		for n := range node {
+			node[n] *= ns
+		}
+	}
+	if es != 1 {
+		for _, to := range arc {
+			for x := range to {
+				to[x] *= es
+			}
+		}
+	}
+	return
+}
+
+// bcBFS returns a single source accumulation function for unweighted
+// betweenness.
+func bcBFS(a AdjacencyList) func(*bcWorker, NI) {
+	return func(w *bcWorker, s NI) {
+		sigma, delta := w.sigma, w.delta
+		if w.dist == nil {
+			w.dist = make([]int, len(a))
+		}
+		d := w.dist
+		for n := range d {
+			d[n] = -1
+			sigma[n] = 0
+			delta[n] = 0
+		}
+		d[s] = 0
+		sigma[s] = 1
+		order := append(w.order[:0], s)
+		for i := 0; i < len(order); i++ {
+			n := order[i]
+			for _, to := range a[n] {
+				if d[to] < 0 {
+					d[to] = d[n] + 1
+					order = append(order, to)
+				}
+				if d[to] == d[n]+1 {
+					sigma[to] += sigma[n]
+				}
+			}
+		}
+		for i := len(order) - 1; i >= 0; i-- {
+			n := order[i]
+			wa := w.arc[n]
+			for x, to := range a[n] {
+				if d[to] == d[n]+1 {
+					c := sigma[n] / sigma[to] * (1 + delta[to])
+					wa[x] += c
+					delta[n] += c
+				}
+			}
+			if n != s {
+				w.node[n] += delta[n]
+			}
+		}
+		w.order = order
+	}
+}
+
+// bcDijkstra returns a single source accumulation function for weighted
+// betweenness.
+func bcDijkstra(a LabeledAdjacencyList, wf WeightFunc) func(*bcWorker, NI) {
+	return func(w *bcWorker, s NI) {
+		sigma, delta := w.sigma, w.delta
+		if w.r == nil {
+			w.r = make([]tentResult, len(a))
+		}
+		r := w.r
+		for n := range r {
+			r[n] = tentResult{dist: math.Inf(1), nx: NI(n), fx: -1}
+			sigma[n] = 0
+			delta[n] = 0
+		}
+		r[s].dist = 0
+		sigma[s] = 1
+		order := w.order[:0]
+		t := tent{&r[s]}
+		r[s].fx = 0
+		for len(t) > 0 {
+			cr := heap.Pop(&t).(*tentResult)
+			cr.done = true
+			n := cr.nx
+			order = append(order, n)
+			for _, h := range a[n] {
+				hr := &r[h.To]
+				if hr.done {
+					continue
+				}
+				switch d := cr.dist + wf(h.Label); {
+				case d < hr.dist:
+					hr.dist = d
+					sigma[h.To] = sigma[n]
+					if hr.fx < 0 {
+						heap.Push(&t, hr)
+					} else {
+						heap.Fix(&t, hr.fx)
+					}
+				case d == hr.dist:
+					sigma[h.To] += sigma[n]
+				}
+			}
+		}
+		for i := len(order) - 1; i >= 0; i-- {
+			n := order[i]
+			dn := r[n].dist
+			wa := w.arc[n]
+			for x, h := range a[n] {
+				if hr := &r[h.To]; hr.done && h.To != n &&
+					hr.dist == dn+wf(h.Label) {
+					c := sigma[n] / sigma[h.To] * (1 + delta[h.To])
+					wa[x] += c
+					delta[n] += c
+				}
+			}
+			if n != s {
+				w.node[n] += delta[n]
+			}
+		}
+		w.order = order
+	}
+}
+
+// bcEdges combines arc results of an undirected graph into edge results.
+//
+// Reciprocal arcs, including those of parallel edges with the same label,
+// are assigned the mean of their values.  This both sums the two directions
+// of each edge and halves the sum for counting each node pair twice.
+func bcEdges(arc [][]float64, half func(fr NI, x int) (NI, LI)) {
+	type key struct {
+		n1, n2 NI
+		l      LI
+	}
+	type sum struct {
+		s float64
+		n int
+	}
+	m := map[key]*sum{}
+	k := func(fr NI, x int) key {
+		to, l := half(fr, x)
+		if to < fr {
+			fr, to = to, fr
+		}
+		return key{fr, to, l}
+	}
+	for fr, to := range arc {
+		for x, c := range to {
+			kx := k(NI(fr), x)
+			s := m[kx]
+			if s == nil {
+				s = &sum{}
+				m[kx] = s
+			}
+			s.s += c
+			s.n++
+		}
+	}
+	for fr, to := range arc {
+		for x := range to {
+			s := m[k(NI(fr), x)]
+			to[x] = s.s / float64(s.n)
+		}
+	}
+}
+
+// Betweenness computes betweenness centrality for each node and each arc
+// of a directed graph, where each arc has length 1.
+//
+// Betweenness of a node n is the sum over all ordered pairs of other nodes
+// s, t, of the fraction of shortest paths from s to t passing through n.
+// Betweenness of an arc is the sum over all ordered pairs of nodes of the
+// fraction of shortest paths using the arc.  Parallel arcs are distinct
+// arcs and so multiply the count of shortest paths.
+//
+// See BetweennessOptions for normalization, sampling, and parallel
+// execution.
+//
+// Returned are betweenness values for each node and for each arc.  Arc
+// values are in a slice parallel to g; that is, arc[n][x] is the value for
+// the arc g.AdjacencyList[n][x].
+func (g Directed) Betweenness(opt *BetweennessOptions) (node []float64, arc [][]float64) {
+	a := g.AdjacencyList
+	return betweenness(len(a), func(n NI) int { return len(a[n]) },
+		opt, false, bcBFS(a))
+}
+
+// Betweenness computes betweenness centrality for each node and each edge
+// of an undirected graph, where each edge has length 1.
+//
+// Betweenness of a node n is the sum over all unordered pairs of other
+// nodes s, t, of the fraction of shortest paths between s and t passing
+// through n.  Betweenness of an edge is the sum over all unordered pairs of
+// nodes of the fraction of shortest paths using the edge.
+//
+// See BetweennessOptions for normalization, sampling, and parallel
+// execution.
+//
+// Returned are betweenness values for each node and for each edge.  Edge
+// values are in a slice parallel to g; that is, edge[n][x] is the value
+// for the edge represented by the arc g.AdjacencyList[n][x].  Both arcs
+// representing an edge have the same value.
+func (g Undirected) Betweenness(opt *BetweennessOptions) (node []float64, edge [][]float64) {
+	a := g.AdjacencyList
+	node, edge = betweenness(len(a), func(n NI) int { return len(a[n]) },
+		opt, true, bcBFS(a))
+	bcEdges(edge, func(fr NI, x int) (NI, LI) { return a[fr][x], 0 })
+	return
+}
+
+// Betweenness computes betweenness centrality for each node and each arc
+// of a directed graph with weighted arcs.
+//
+// Arc weights are given by WeightFunc w and must be positive.  Shortest
+// paths are those of minimum total weight, where path weights are compared
+// exactly.  W is called concurrently when more than one worker is used and
+// so must be safe for concurrent use.
+//
+// Otherwise the method is similar to Directed.Betweenness.  Returned are
+// betweenness values for each node and for each arc.  Arc values are in a
+// slice parallel to g; that is, arc[n][x] is the value for the arc
+// g.LabeledAdjacencyList[n][x].
+func (g LabeledDirected) Betweenness(w WeightFunc, opt *BetweennessOptions) (node []float64, arc [][]float64) {
+	a := g.LabeledAdjacencyList
+	return betweenness(len(a), func(n NI) int { return len(a[n]) },
+		opt, false, bcDijkstra(a, w))
+}
+
+// Betweenness computes betweenness centrality for each node and each edge
+// of an undirected graph with weighted edges.
+//
+// Edge weights are given by WeightFunc w and must be positive.  Shortest
+// paths are those of minimum total weight, where path weights are compared
+// exactly.  W is called concurrently when more than one worker is used and
+// so must be safe for concurrent use.
+//
+// Otherwise the method is similar to Undirected.Betweenness.  Returned are
+// betweenness values for each node and for each edge.  Edge values are in
+// a slice parallel to g; that is, edge[n][x] is the value for the edge
+// represented by the arc g.LabeledAdjacencyList[n][x].  Both arcs
+// representing an edge have the same value.
+func (g LabeledUndirected) Betweenness(w WeightFunc, opt *BetweennessOptions) (node []float64, edge [][]float64) {
+	a := g.LabeledAdjacencyList
+	node, edge = betweenness(len(a), func(n NI) int { return len(a[n]) },
+		opt, true, bcDijkstra(a, w))
+	bcEdges(edge, func(fr NI, x int) (NI, LI) {
+		h := a[fr][x]
+		return h.To, h.Label
+	})
+	return
+}
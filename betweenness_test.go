@@ -0,0 +1,241 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Betweenness() {
+	//   0   3
+	//   |\ /|
+	//   | 2 |
+	//   |/ \|
+	//   1   4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	node, edge := g.Betweenness(nil)
+	fmt.Println(node)
+	for n, to := range g.AdjacencyList {
+		for x, to := range to {
+			if graph.NI(n) < to {
+				fmt.Println(n, to, edge[n][x])
+			}
+		}
+	}
+	node, _ = g.Betweenness(&graph.BetweennessOptions{Normalize: true})
+	fmt.Println(node[2])
+	// Output:
+	// [0 0 4 0 0]
+	// 0 1 1
+	// 0 2 3
+	// 1 2 3
+	// 2 3 3
+	// 2 4 3
+	// 3 4 1
+	// 0.6666666666666666
+}
+
+func ExampleLabeledDirected_Betweenness() {
+	//   0 --(1)--> 1 --(1)--> 3
+	//    \                   ^
+	//     ---(3)--> 2 --(1)-/
+	//
+	// weights are the labels.
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 2, Label: 3}},
+		1: {{To: 3, Label: 1}},
+		2: {{To: 3, Label: 1}},
+		3: {},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	node, arc := g.Betweenness(w, nil)
+	fmt.Println(node)
+	fmt.Println(arc)
+	// Output:
+	// [0 1 0 0]
+	// [[2 1] [2] [1] []]
+}
+
+// bcBF computes betweenness by enumerating simple paths.  Results are
+// per arc, with undirected pairs counted in both directions.
+func bcBF(a graph.LabeledAdjacencyList, w graph.WeightFunc) (node []float64, arc [][]float64) {
+	node = make([]float64, len(a))
+	arc = make([][]float64, len(a))
+	for n := range arc {
+		arc[n] = make([]float64, len(a[n]))
+	}
+	type step struct {
+		n graph.NI
+		x int
+	}
+	for s := range a {
+		paths := make([][][]step, len(a))
+		best := make([]float64, len(a))
+		for t := range best {
+			best[t] = math.Inf(1)
+		}
+		on := make([]bool, len(a))
+		var p []step
+		var dfs func(n graph.NI, d float64)
+		dfs = func(n graph.NI, d float64) {
+			on[n] = true
+			for x, h := range a[n] {
+				if on[h.To] {
+					continue
+				}
+				p = append(p, step{n, x})
+				d2 := d + w(h.Label)
+				switch {
+				case d2 < best[h.To]:
+					best[h.To] = d2
+					paths[h.To] = [][]step{append([]step{}, p...)}
+				case d2 == best[h.To]:
+					paths[h.To] = append(paths[h.To], append([]step{}, p...))
+				}
+				dfs(h.To, d2)
+				p = p[:len(p)-1]
+			}
+			on[n] = false
+		}
+		dfs(graph.NI(s), 0)
+		for _, ps := range paths {
+			f := 1 / float64(len(ps))
+			for _, p := range ps {
+				for i, st := range p {
+					if i > 0 {
+						node[st.n] += f
+					}
+					arc[st.n][st.x] += f
+				}
+			}
+		}
+	}
+	return
+}
+
+func bcSame(a, b []float64) bool {
+	for i, x := range a {
+		if math.Abs(x-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBetweenness(t *testing.T) {
+	r := rand.New(rand.NewSource(37))
+	w := func(l graph.LI) float64 { return float64(l%3 + 1) }
+	unit := func(graph.LI) float64 { return 1 }
+	for i := 0; i < 40; i++ {
+		n := 2 + r.Intn(6)
+		// directed
+		var ld graph.LabeledDirected
+		ld.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for l := 0; l < 2*n; l++ {
+			fr, to := r.Intn(n), r.Intn(n)
+			ld.LabeledAdjacencyList[fr] = append(ld.LabeledAdjacencyList[fr],
+				graph.Half{To: graph.NI(to), Label: graph.LI(l)})
+		}
+		d := ld.Unlabeled()
+		for _, opt := range []*graph.BetweennessOptions{nil, {Workers: 3}} {
+			wantN, wantA := bcBF(ld.LabeledAdjacencyList, w)
+			gotN, gotA := ld.Betweenness(w, opt)
+			if !bcSame(gotN, wantN) {
+				t.Fatal("LabeledDirected node", ld, gotN, wantN)
+			}
+			for fr := range wantA {
+				if !bcSame(gotA[fr], wantA[fr]) {
+					t.Fatal("LabeledDirected arc", ld, gotA, wantA)
+				}
+			}
+			wantN, wantA = bcBF(ld.LabeledAdjacencyList, unit)
+			gotN, gotA = d.Betweenness(opt)
+			if !bcSame(gotN, wantN) {
+				t.Fatal("Directed node", d, gotN, wantN)
+			}
+			for fr := range wantA {
+				if !bcSame(gotA[fr], wantA[fr]) {
+					t.Fatal("Directed arc", d, gotA, wantA)
+				}
+			}
+		}
+		// undirected, with edges identified by label
+		var lu graph.LabeledUndirected
+		lu.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for l := 0; l < n+r.Intn(n); l++ {
+			lu.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(l))
+		}
+		u := graph.Undirected{lu.Unlabeled()}
+		for _, c := range []struct {
+			wf  graph.WeightFunc
+			got func() ([]float64, [][]float64)
+		}{
+			{w, func() ([]float64, [][]float64) { return lu.Betweenness(w, nil) }},
+			{unit, func() ([]float64, [][]float64) {
+				return u.Betweenness(&graph.BetweennessOptions{Workers: 2})
+			}},
+		} {
+			wantN, wantA := bcBF(lu.LabeledAdjacencyList, c.wf)
+			edge := map[graph.LI]float64{}
+			for fr, to := range lu.LabeledAdjacencyList {
+				for x, h := range to {
+					edge[h.Label] += wantA[fr][x] / 2
+				}
+			}
+			gotN, gotE := c.got()
+			for n := range wantN {
+				wantN[n] /= 2
+			}
+			if !bcSame(gotN, wantN) {
+				t.Fatal("undirected node", lu, gotN, wantN)
+			}
+			for fr, to := range lu.LabeledAdjacencyList {
+				for x, h := range to {
+					if h.To != graph.NI(fr) &&
+						math.Abs(gotE[fr][x]-edge[h.Label]) > 1e-9 {
+						t.Fatal("undirected edge", lu, gotE, edge)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBetweennessSampled(t *testing.T) {
+	g := graph.GnmUndirected(30, 60, rand.New(rand.NewSource(3)))
+	exact, _ := g.Betweenness(nil)
+	all, _ := g.Betweenness(&graph.BetweennessOptions{Samples: 30})
+	if !bcSame(all, exact) {
+		t.Fatal("Samples = n not exact")
+	}
+	opt := &graph.BetweennessOptions{Samples: 10,
+		Rand: rand.New(rand.NewSource(1))}
+	s1, _ := g.Betweenness(opt)
+	opt.Rand = rand.New(rand.NewSource(1))
+	opt.Workers = 4
+	s2, _ := g.Betweenness(opt)
+	if !bcSame(s1, s2) {
+		t.Fatal("sampled results depend on workers")
+	}
+	var sum, sumExact float64
+	for n, b := range s1 {
+		sum += b
+		sumExact += exact[n]
+	}
+	if sum <= 0 || math.Abs(sum-sumExact) > sumExact/2 {
+		t.Fatal("sampled estimate", sum, sumExact)
+	}
+}
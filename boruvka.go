@@ -0,0 +1,235 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// boruvka.go has a parallel implementation of Borůvka's algorithm.
+//
+// Most of this library is single threaded.  Borůvka's algorithm is an
+// exception because its rounds of cheapest edge search and contraction
+// parallelize naturally and it is a practical choice for very large graphs.
+
+// bEdge is an edge for Boruvka.  Edges are totally ordered by weight and
+// then by id so that ties are broken consistently.
+type bEdge struct {
+	n1, n2 NI
+	label  LI
+	wt     float64
+	id     int
+}
+
+func (e *bEdge) less(f *bEdge) bool {
+	return e.wt < f.wt || e.wt == f.wt && e.id < f.id
+}
+
+// parallelRange calls f concurrently on nw subranges partitioning [0, n)
+// and waits for all calls to return.
+func parallelRange(nw, n int, f func(w, lo, hi int)) {
+	var wg sync.WaitGroup
+	for w := 0; w < nw; w++ {
+		lo, hi := w*n/nw, (w+1)*n/nw
+		wg.Add(1)
+		go func(w, lo, hi int) {
+			f(w, lo, hi)
+			wg.Done()
+		}(w, lo, hi)
+	}
+	wg.Wait()
+}
+
+// Boruvka implements Borůvka's algorithm for constructing a minimum spanning
+// forest on an undirected graph.
+//
+// The cheapest edge search and contraction of each round are divided among
+// nWorkers goroutines.  Cheapest edges are found with atomic updates of a
+// single shared entry per component, and components are contracted by
+// pointer jumping along cheapest edges, so memory is O(n+m) regardless of
+// nWorkers.  Only adding the chosen edges to the returned forest is done by
+// a single goroutine.  If nWorkers is less than 1, runtime.GOMAXPROCS(0)
+// is used.  WeightFunc w is called concurrently and so must be safe for
+// concurrent use.  Loops are ignored and parallel edges are allowed.
+//
+// Ties between equal weights are broken consistently so the result is
+// always a minimum spanning forest.  For graphs with distinct edge weights
+// the minimum spanning forest is unique and the result is the same as that
+// of Kruskal or Prim.
+//
+// The forest is returned as an undirected graph.
+//
+// Also returned is a total distance for the returned forest.
+func (g LabeledUndirected) Boruvka(w WeightFunc, nWorkers int) (spanningForest LabeledUndirected, dist float64) {
+	a := g.LabeledAdjacencyList
+	if nWorkers < 1 {
+		nWorkers = runtime.GOMAXPROCS(0)
+	}
+	spanningForest.LabeledAdjacencyList = make(LabeledAdjacencyList, len(a))
+	// edge list, built in parallel with a count pass then a fill pass.
+	cnt := make([]int, nWorkers+1)
+	parallelRange(nWorkers, len(a), func(wk, lo, hi int) {
+		c := 0
+		for fr := lo; fr < hi; fr++ {
+			for _, to := range a[fr] {
+				if to.To > NI(fr) {
+					c++
+				}
+			}
+		}
+		cnt[wk+1] = c
+	})
+	for wk := 1; wk <= nWorkers; wk++ {
+		cnt[wk] += cnt[wk-1]
+	}
+	edges := make([]bEdge, cnt[nWorkers])
+	parallelRange(nWorkers, len(a), func(wk, lo, hi int) {
+		x := cnt[wk]
+		for fr := lo; fr < hi; fr++ {
+			for _, to := range a[fr] {
+				if to.To > NI(fr) {
+					edges[x] = bEdge{NI(fr), to.To, to.Label, w(to.Label), x}
+					x++
+				}
+			}
+		}
+	})
+	// comp is the component of each node, identified by a representative
+	// node.  best holds the index into edges of the cheapest edge leaving
+	// each component, or -1.  succ is the component each component is
+	// contracted into.  For each current representative c, succ[c] == c at
+	// the start of each round.
+	comp := make([]NI, len(a))
+	succ := make([]NI, len(a))
+	nxt := make([]NI, len(a))
+	best := make([]int64, len(a))
+	for n := range comp {
+		comp[n] = NI(n)
+		succ[n] = NI(n)
+		best[n] = -1
+	}
+	act := make([][]NI, nWorkers)  // components with a cheapest edge
+	add := make([][]int, nWorkers) // edges to add to the forest
+	changed := make([]bool, nWorkers)
+	var active []NI
+	var added []int
+	for len(edges) > 0 {
+		// cheapest edge leaving each component, by atomic compare and swap
+		parallelRange(nWorkers, len(edges), func(_, lo, hi int) {
+			for x := lo; x < hi; x++ {
+				e := &edges[x]
+				for _, c := range [2]NI{comp[e.n1], comp[e.n2]} {
+					for {
+						old := atomic.LoadInt64(&best[c])
+						if old >= 0 && !e.less(&edges[old]) ||
+							atomic.CompareAndSwapInt64(&best[c], old, int64(x)) {
+							break
+						}
+					}
+				}
+			}
+		})
+		parallelRange(nWorkers, len(a), func(wk, lo, hi int) {
+			act[wk] = act[wk][:0]
+			for c := lo; c < hi; c++ {
+				if x := best[c]; x >= 0 {
+					act[wk] = append(act[wk], NI(c))
+					e := &edges[x]
+					if o := comp[e.n1]; o != NI(c) {
+						succ[c] = o
+					} else {
+						succ[c] = comp[e.n2]
+					}
+				}
+			}
+		})
+		active = active[:0]
+		for _, ac := range act {
+			active = append(active, ac...)
+		}
+		// following cheapest edges from each component leads to a pair of
+		// components with the same cheapest edge.  the lower numbered of
+		// the pair becomes the root of the contracted component.  each
+		// other component adds its cheapest edge to the forest.
+		parallelRange(nWorkers, len(active), func(wk, lo, hi int) {
+			add[wk] = add[wk][:0]
+			for _, c := range active[lo:hi] {
+				s := succ[c]
+				if succ[s] == c && c < s {
+					nxt[c] = c
+				} else {
+					nxt[c] = s
+					add[wk] = append(add[wk], int(best[c]))
+				}
+			}
+		})
+		// pointer jumping to the roots
+		for more := true; more; {
+			parallelRange(nWorkers, len(active), func(_, lo, hi int) {
+				for _, c := range active[lo:hi] {
+					succ[c] = nxt[c]
+				}
+			})
+			parallelRange(nWorkers, len(active), func(wk, lo, hi int) {
+				changed[wk] = false
+				for _, c := range active[lo:hi] {
+					if s := succ[succ[c]]; s != succ[c] {
+						nxt[c] = s
+						changed[wk] = true
+					}
+				}
+			})
+			more = false
+			for _, ch := range changed {
+				more = more || ch
+			}
+		}
+		// add edges to the forest in order of increasing weight
+		added = added[:0]
+		for _, xs := range add {
+			added = append(added, xs...)
+		}
+		sort.Slice(added, func(i, j int) bool {
+			return edges[added[i]].less(&edges[added[j]])
+		})
+		for _, x := range added {
+			e := &edges[x]
+			spanningForest.AddEdge(Edge{e.n1, e.n2}, e.label)
+			dist += e.wt
+		}
+		// relabel components and drop edges within components
+		parallelRange(nWorkers, len(a), func(_, lo, hi int) {
+			for n := lo; n < hi; n++ {
+				comp[n] = succ[comp[n]]
+			}
+		})
+		parallelRange(nWorkers, len(active), func(_, lo, hi int) {
+			for _, c := range active[lo:hi] {
+				best[c] = -1
+				succ[c] = comp[c] // c itself if c is still a representative
+			}
+		})
+		parallelRange(nWorkers, len(edges), func(wk, lo, hi int) {
+			x := lo
+			for _, e := range edges[lo:hi] {
+				if comp[e.n1] != comp[e.n2] {
+					edges[x] = e
+					x++
+				}
+			}
+			cnt[wk+1] = x - lo
+		})
+		// compact the retained edges of each worker range
+		x := 0
+		for wk := 0; wk < nWorkers; wk++ {
+			lo := wk * len(edges) / nWorkers
+			x += copy(edges[x:], edges[lo:lo+cnt[wk+1]])
+		}
+		edges = edges[:x]
+	}
+	return
+}
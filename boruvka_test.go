@@ -0,0 +1,86 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledUndirected_Boruvka() {
+	//       (10)
+	//     0------4----\
+	//     |     /|     \(70)
+	// (30)| (40) |(60)  \
+	//     |/     |      |
+	//     1------2------3
+	//       (50)   (20)
+	w := func(l graph.LI) float64 { return float64(l) }
+	// undirected graph
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 30)
+	g.AddEdge(graph.Edge{0, 4}, 10)
+	g.AddEdge(graph.Edge{1, 2}, 50)
+	g.AddEdge(graph.Edge{1, 4}, 40)
+	g.AddEdge(graph.Edge{2, 3}, 20)
+	g.AddEdge(graph.Edge{2, 4}, 60)
+	g.AddEdge(graph.Edge{3, 4}, 70)
+
+	t, dist := g.Boruvka(w, 2)
+
+	fmt.Println("spanning tree as undirected graph:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total distance: ", dist)
+	// Output:
+	// spanning tree as undirected graph:
+	// 0 [{4 10} {1 30}]
+	// 1 [{0 30} {2 50}]
+	// 2 [{3 20} {1 50}]
+	// 3 [{2 20}]
+	// 4 [{0 10}]
+	// total distance:  110
+}
+
+func TestBoruvka(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(200)
+		distinct := i%2 == 0
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		m := r.Intn(3 * n)
+		labels := r.Perm(m)
+		for j := 0; j < m; j++ {
+			l := graph.LI(labels[j])
+			if !distinct {
+				l = graph.LI(r.Intn(5))
+			}
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))}, l)
+		}
+		w := func(l graph.LI) float64 { return float64(l) }
+		kt, kd := g.Kruskal(w)
+		bt, bd := g.Boruvka(w, 1+r.Intn(8))
+		if bd != kd {
+			t.Fatal("dist", bd, kd)
+		}
+		if bt.Size() != kt.Size() {
+			t.Fatal("size", bt.Size(), kt.Size())
+		}
+		if _, nc := bt.ConnectedComponentInts(); bt.Size() != n-nc {
+			t.Fatal("not a forest")
+		}
+		if distinct {
+			kt.Edges(func(e graph.LabeledEdge) {
+				if ok, _, _ := bt.HasEdgeLabel(e.N1, e.N2, e.LI); !ok {
+					t.Fatal("different forest")
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"sort"
+
+	"github.com/soniakeys/bits"
+)
+
+// cliqueperc.go has k-clique percolation, finding overlapping communities.
+//
+// A k-clique community is a union of k-cliques that can be reached from
+// one another through a series of adjacent k-cliques, where adjacent
+// cliques share k-1 nodes.  Communities may overlap, that is, a node may
+// be in more than one community.
+//
+// Following Palla et al., communities are found from maximal cliques
+// rather than from all k-cliques.  Maximal cliques of at least k nodes are
+// adjacent if they share at least k-1 nodes, and communities are the unions
+// of connected components of maximal cliques.
+
+// KCliqueCommunities finds k-clique communities in an undirected graph by
+// the clique percolation method.
+//
+// The graph must not contain parallel edges or loops.  For k = 2,
+// communities are the connected components with at least one edge.  If k is
+// less than 2, KCliqueCommunities returns nil.
+//
+// Maximal cliques are found with BronKerbosch3.
+//
+// Communities are returned as bitmaps of member nodes, ordered
+// lexicographically by member node numbers.
+//
+// See also KCliqueCommunitiesRange for communities for a range of k.
+func (g Undirected) KCliqueCommunities(k int) []bits.Bits {
+	r := g.KCliqueCommunitiesRange(k, k)
+	if r == nil {
+		return nil
+	}
+	return r[0]
+}
+
+// KCliqueCommunitiesRange finds k-clique communities in an undirected graph
+// for each k from kMin through kMax.
+//
+// The graph must not contain parallel edges or loops.  If kMin is less than
+// 2 or kMax is less than kMin, KCliqueCommunitiesRange returns nil.
+//
+// Maximal cliques are found just once and overlaps between them are
+// computed just once, making this more efficient than repeated calls to
+// KCliqueCommunities.
+//
+// Returned is a list of communities for each k, with communities for k
+// at index k - kMin.  Communities for each k are as returned by
+// KCliqueCommunities.
+func (g Undirected) KCliqueCommunitiesRange(kMin, kMax int) [][]bits.Bits {
+	if kMin < 2 || kMax < kMin {
+		return nil
+	}
+	a := g.AdjacencyList
+	// maximal cliques with at least kMin nodes
+	var cliques []bits.Bits
+	g.BronKerbosch3(g.BKPivotMaxDegree, func(c bits.Bits) bool {
+		if c.OnesCount() >= kMin {
+			cc := bits.New(len(a))
+			cc.Set(c)
+			cliques = append(cliques, cc)
+		}
+		return true
+	})
+	size := make([]int, len(cliques))
+	for i, c := range cliques {
+		size[i] = c.OnesCount()
+	}
+	// cliques containing each node
+	in := make([][]int, len(a))
+	for i, c := range cliques {
+		c.IterateOnes(func(n int) bool {
+			in[n] = append(in[n], i)
+			return true
+		})
+	}
+	// overlaps of cliques with at least kMin-1 shared nodes
+	type pair struct{ c1, c2 int }
+	shared := map[pair]int{}
+	for _, cs := range in {
+		for x, c1 := range cs {
+			for _, c2 := range cs[x+1:] {
+				shared[pair{c1, c2}]++
+			}
+		}
+	}
+	var overlaps []pair
+	var ov []int
+	for p, s := range shared {
+		if s >= kMin-1 {
+			overlaps = append(overlaps, p)
+			ov = append(ov, s)
+		}
+	}
+	r := make([][]bits.Bits, kMax-kMin+1)
+	for k := kMin; k <= kMax; k++ {
+		u := NewUnionFind(len(cliques))
+		for i, p := range overlaps {
+			if ov[i] >= k-1 && size[p.c1] >= k && size[p.c2] >= k {
+				u.Union(NI(p.c1), NI(p.c2))
+			}
+		}
+		comm := map[NI]bits.Bits{}
+		var cs []bits.Bits
+		for i, c := range cliques {
+			if size[i] < k {
+				continue
+			}
+			rep := u.Find(NI(i))
+			b, ok := comm[rep]
+			if !ok {
+				b = bits.New(len(a))
+				cs = append(cs, b)
+				comm[rep] = b
+			}
+			b.Or(b, c)
+		}
+		sort.Slice(cs, func(i, j int) bool {
+			// lexicographic by member nodes.  overlapping communities
+			// can have the same lowest node.
+			x, y := cs[i], cs[j]
+			for n1, n2 := x.OneFrom(0), y.OneFrom(0); n2 >= 0; n1, n2 = x.OneFrom(n1+1), y.OneFrom(n2+1) {
+				if n1 != n2 {
+					return n1 < n2 // n1 < 0 if x is a prefix of y
+				}
+			}
+			return false
+		})
+		r[k-kMin] = cs
+	}
+	return r
+}
@@ -0,0 +1,161 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/bits"
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_KCliqueCommunities() {
+	// K4 on nodes 0-3 and K4 on nodes 3-6 sharing node 3, triangle
+	// 6 7 8 sharing node 6.
+	var g graph.Undirected
+	for _, c := range [][]graph.NI{{0, 1, 2, 3}, {3, 4, 5, 6}, {6, 7, 8}} {
+		for i, n1 := range c {
+			for _, n2 := range c[i+1:] {
+				g.AddEdge(n1, n2)
+			}
+		}
+	}
+	for _, c := range g.KCliqueCommunities(3) {
+		fmt.Println(c.Slice())
+	}
+	// Output:
+	// [0 1 2 3]
+	// [3 4 5 6]
+	// [6 7 8]
+}
+
+func ExampleUndirected_KCliqueCommunitiesRange() {
+	// two K4s on nodes 0-3 and 2-5 sharing edge 2 3, and a triangle 5 6 7
+	var g graph.Undirected
+	for _, e := range []graph.Edge{
+		{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3},
+		{2, 4}, {2, 5}, {3, 4}, {3, 5}, {4, 5},
+		{5, 6}, {5, 7}, {6, 7},
+	} {
+		g.AddEdge(e.N1, e.N2)
+	}
+	for i, cs := range g.KCliqueCommunitiesRange(2, 4) {
+		fmt.Print("k=", i+2, ":")
+		for _, c := range cs {
+			fmt.Print(" ", c.Slice())
+		}
+		fmt.Println()
+	}
+	// Output:
+	// k=2: [0 1 2 3 4 5 6 7]
+	// k=3: [0 1 2 3 4 5] [5 6 7]
+	// k=4: [0 1 2 3] [2 3 4 5]
+}
+
+// kCliqueBF finds k-clique communities by enumerating all k-cliques.
+func kCliqueBF(g graph.Undirected, k int) []string {
+	n := g.Order()
+	adj := make([][]bool, n)
+	for i := range adj {
+		adj[i] = make([]bool, n)
+	}
+	for u, to := range g.AdjacencyList {
+		for _, v := range to {
+			adj[u][v] = true
+		}
+	}
+	var cliques [][]int
+	var rec func(c []int, from int)
+	rec = func(c []int, from int) {
+		if len(c) == k {
+			cliques = append(cliques, append([]int{}, c...))
+			return
+		}
+	next:
+		for v := from; v < n; v++ {
+			for _, u := range c {
+				if !adj[u][v] {
+					continue next
+				}
+			}
+			rec(append(c, v), v+1)
+		}
+	}
+	rec(nil, 0)
+	u := graph.NewUnionFind(len(cliques))
+	for i, c1 := range cliques {
+		for j := i + 1; j < len(cliques); j++ {
+			s := 0
+			for _, a := range c1 {
+				for _, b := range cliques[j] {
+					if a == b {
+						s++
+					}
+				}
+			}
+			if s == k-1 {
+				u.Union(graph.NI(i), graph.NI(j))
+			}
+		}
+	}
+	comm := map[graph.NI]bits.Bits{}
+	for i, c := range cliques {
+		r := u.Find(graph.NI(i))
+		b, ok := comm[r]
+		if !ok {
+			b = bits.New(n)
+			comm[r] = b
+		}
+		for _, v := range c {
+			b.SetBit(v, 1)
+		}
+	}
+	var s []string
+	for _, b := range comm {
+		s = append(s, fmt.Sprint(b.Slice()))
+	}
+	return s
+}
+
+func TestKCliqueCommunities(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for tc := 0; tc < 100; tc++ {
+		n := 2 + r.Intn(10)
+		p := r.Float64()
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		for u := 0; u < n; u++ {
+			for v := u + 1; v < n; v++ {
+				if r.Float64() < p {
+					g.AddEdge(graph.NI(u), graph.NI(v))
+				}
+			}
+		}
+		got := g.KCliqueCommunitiesRange(2, 6)
+		for k := 2; k <= 6; k++ {
+			cs := got[k-2]
+			want := map[string]bool{}
+			for _, s := range kCliqueBF(g, k) {
+				want[s] = true
+			}
+			if len(cs) != len(want) {
+				t.Fatalf("n %d k %d: %d communities, want %d",
+					n, k, len(cs), len(want))
+			}
+			for i, c := range cs {
+				if !want[fmt.Sprint(c.Slice())] {
+					t.Fatalf("n %d k %d: unexpected community %v",
+						n, k, c.Slice())
+				}
+				if i > 0 && fmt.Sprint(c.Slice()) == fmt.Sprint(cs[i-1].Slice()) {
+					t.Fatalf("n %d k %d: duplicate community", n, k)
+				}
+			}
+			if one := g.KCliqueCommunities(k); len(one) != len(cs) {
+				t.Fatalf("n %d k %d: KCliqueCommunities differs", n, k)
+			}
+		}
+	}
+}
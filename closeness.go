@@ -0,0 +1,154 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// closeness.go has distance based centrality measures, closeness and
+// harmonic centrality.
+//
+// Both are computed from distances from a node to all nodes reachable from
+// it, by breadth first search on an AdjacencyList or by Dijkstra's
+// algorithm on a LabeledAdjacencyList.  For a directed graph these are
+// distances out from a node.  To measure distances in to a node, use the
+// transpose of the graph.
+
+// distanceSums calls sd for each of nodes, or for all nodes of g if nodes
+// is nil, and collects results of f applied to the returned sums.
+func distanceSums(order int, nodes []NI, sd func(NI) (sum, harm float64, nReached int), f func(sum, harm float64, nReached int) float64) []float64 {
+	if nodes == nil {
+		nodes = make([]NI, order)
+		for n := range nodes {
+			nodes[n] = NI(n)
+		}
+	}
+	c := make([]float64, len(nodes))
+	for i, n := range nodes {
+		c[i] = f(sd(n))
+	}
+	return c
+}
+
+// bfsDistanceSums returns a function computing, for a start node, the sum
+// of distances to reachable nodes, the sum of reciprocal distances, and
+// the number of nodes reached, including the start node.
+func (g AdjacencyList) bfsDistanceSums() func(NI) (float64, float64, int) {
+	d := make([]int, len(g))
+	for n := range d {
+		d[n] = -1
+	}
+	var q []NI
+	return func(start NI) (sum, harm float64, nReached int) {
+		d[start] = 0
+		q = append(q[:0], start)
+		for i := 0; i < len(q); i++ {
+			n := q[i]
+			for _, to := range g[n] {
+				if d[to] < 0 {
+					d[to] = d[n] + 1
+					sum += float64(d[to])
+					harm += 1 / float64(d[to])
+					q = append(q, to)
+				}
+			}
+		}
+		for _, n := range q {
+			d[n] = -1
+		}
+		return sum, harm, len(q)
+	}
+}
+
+// dijkstraDistanceSums is the weighted equivalent of bfsDistanceSums.
+func (g LabeledAdjacencyList) dijkstraDistanceSums(w WeightFunc) func(NI) (float64, float64, int) {
+	return func(start NI) (sum, harm float64, nReached int) {
+		f, _, dist, nReached := g.Dijkstra(start, -1, w)
+		for n, p := range f.Paths {
+			if p.Len > 1 {
+				sum += dist[n]
+				harm += 1 / dist[n]
+			}
+		}
+		return sum, harm, nReached
+	}
+}
+
+// closeness computes closeness with the Wasserman and Faust correction.
+func closeness(order int) func(sum, harm float64, nReached int) float64 {
+	return func(sum, _ float64, nReached int) float64 {
+		if sum == 0 {
+			return 0
+		}
+		r := float64(nReached - 1)
+		return r / sum * r / float64(order-1)
+	}
+}
+
+func harmonic(_, harm float64, _ int) float64 {
+	return harm
+}
+
+// Closeness computes closeness centrality of nodes of a graph where each
+// arc has length 1.
+//
+// Closeness of a node n is the reciprocal of the mean distance from n to
+// other nodes reachable from n.  For graphs that are not connected, the
+// value is scaled by the fraction of other nodes that are reachable, as
+// suggested by Wasserman and Faust.  That is, with r nodes reachable from n
+// not counting n itself, closeness is
+//
+//	(r / sum of distances) * r / (order - 1)
+//
+// For a connected graph this is simply the reciprocal of the mean distance.
+// Closeness of a node that reaches no other nodes is 0.
+//
+// Closeness is computed for each node of argument nodes, or for all nodes
+// if nodes is nil.  The result is parallel to nodes, or indexed by node
+// if nodes is nil.
+//
+// See also Harmonic, which gives more meaningful results for graphs with
+// many components.
+func (g AdjacencyList) Closeness(nodes []NI) []float64 {
+	return distanceSums(len(g), nodes, g.bfsDistanceSums(), closeness(len(g)))
+}
+
+// Harmonic computes harmonic centrality of nodes of a graph where each
+// arc has length 1.
+//
+// Harmonic centrality of a node n is the sum of reciprocal distances from n
+// to all other nodes, where the reciprocal distance to an unreachable node
+// is 0.
+//
+// Harmonic centrality is computed for each node of argument nodes, or for
+// all nodes if nodes is nil.  The result is parallel to nodes, or indexed
+// by node if nodes is nil.
+func (g AdjacencyList) Harmonic(nodes []NI) []float64 {
+	return distanceSums(len(g), nodes, g.bfsDistanceSums(), harmonic)
+}
+
+// Closeness computes closeness centrality of nodes of a graph with
+// weighted arcs.
+//
+// Arc weights are given by WeightFunc w and must be non-negative.  Nodes
+// reached at distance 0 count as reachable nodes but do not contribute to
+// the sum of distances.
+//
+// Otherwise the method is similar to AdjacencyList.Closeness.  Closeness is
+// computed for each node of argument nodes, or for all nodes if nodes is
+// nil.  The result is parallel to nodes, or indexed by node if nodes is nil.
+func (g LabeledAdjacencyList) Closeness(w WeightFunc, nodes []NI) []float64 {
+	return distanceSums(len(g), nodes, g.dijkstraDistanceSums(w),
+		closeness(len(g)))
+}
+
+// Harmonic computes harmonic centrality of nodes of a graph with weighted
+// arcs.
+//
+// Arc weights are given by WeightFunc w and must be positive.
+//
+// Otherwise the method is similar to AdjacencyList.Harmonic.  Harmonic
+// centrality is computed for each node of argument nodes, or for all nodes
+// if nodes is nil.  The result is parallel to nodes, or indexed by node if
+// nodes is nil.
+func (g LabeledAdjacencyList) Harmonic(w WeightFunc, nodes []NI) []float64 {
+	return distanceSums(len(g), nodes, g.dijkstraDistanceSums(w), harmonic)
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_Closeness() {
+	// 0--1--2--3   4--5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(4, 5)
+	fmt.Printf("%.3f\n", g.Closeness(nil))
+	fmt.Printf("%.3f\n", g.Closeness([]graph.NI{2, 4}))
+	// Output:
+	// [0.300 0.450 0.450 0.300 0.200 0.200]
+	// [0.450 0.200]
+}
+
+func ExampleAdjacencyList_Harmonic() {
+	// 0--1--2--3   4--5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(4, 5)
+	fmt.Printf("%.3f\n", g.Harmonic(nil))
+	// Output:
+	// [1.833 2.500 2.500 1.833 1.000 1.000]
+}
+
+func ExampleLabeledAdjacencyList_Closeness() {
+	//   0 --(2)--> 1 --(3)--> 2
+	//
+	// weights are the labels.
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 2}},
+		1: {{To: 2, Label: 3}},
+		2: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	fmt.Printf("%.3f\n", g.Closeness(w, nil))
+	fmt.Printf("%.3f\n", g.Harmonic(w, nil))
+	// Output:
+	// [0.286 0.167 0.000]
+	// [0.700 0.333 0.000]
+}
+
+func TestCloseness(t *testing.T) {
+	r := rand.New(rand.NewSource(38))
+	unit := func(graph.LI) float64 { return 1 }
+	for i := 0; i < 20; i++ {
+		g := graph.GnmDirected(20, 30, r)
+		lg := make(graph.LabeledAdjacencyList, len(g.AdjacencyList))
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				lg[fr] = append(lg[fr], graph.Half{To: to})
+			}
+		}
+		c, lc := g.Closeness(nil), lg.Closeness(unit, nil)
+		h, lh := g.Harmonic(nil), lg.Harmonic(unit, nil)
+		sub := []graph.NI{3, 17, 3}
+		cs, hs := g.Closeness(sub), g.Harmonic(sub)
+		for n := range c {
+			if math.Abs(c[n]-lc[n]) > 1e-12 || math.Abs(h[n]-lh[n]) > 1e-12 {
+				t.Fatal(g, n, c[n], lc[n], h[n], lh[n])
+			}
+		}
+		for i, n := range sub {
+			if cs[i] != c[n] || hs[i] != h[n] {
+				t.Fatal("subset", g, n)
+			}
+		}
+	}
+}
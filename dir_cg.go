@@ -593,6 +593,79 @@ func (g LabeledDirected) InDegree() []int {
 	return ind
 }
 
+// InCoreNumbers computes the in-degree core number of each node of a
+// directed graph.
+//
+// The in-degree core number of a node is the largest k such that the node
+// is in a k-in-core, a maximal subgraph where each node has in-degree at
+// least k within the subgraph.
+//
+// A k-in-core can be constructed as the subgraph induced by nodes with
+// in-degree core number at least k, with InduceBits for example.
+//
+// See also OutCoreNumbers and CoreNumbers.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledDirected) InCoreNumbers() []int {
+	a := g.LabeledAdjacencyList
+	return coreNumbers(g.InDegree(), func(n NI, visit func(NI)) {
+		for _, to := range a[n] {
+			visit(to.To)
+		}
+	})
+}
+
+// OutCoreNumbers computes the out-degree core number of each node of a
+// directed graph.
+//
+// The out-degree core number of a node is the largest k such that the node
+// is in a k-out-core, a maximal subgraph where each node has out-degree at
+// least k within the subgraph.
+//
+// See also InCoreNumbers and CoreNumbers.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledDirected) OutCoreNumbers() []int {
+	a := g.LabeledAdjacencyList
+	t, _ := g.Transpose()
+	deg := make([]int, len(a))
+	for n, to := range a {
+		deg[n] = len(to)
+	}
+	return coreNumbers(deg, func(n NI, visit func(NI)) {
+		for _, fr := range t.LabeledAdjacencyList[n] {
+			visit(fr.To)
+		}
+	})
+}
+
+// CoreNumbers computes the total degree core number of each node of a
+// directed graph.
+//
+// The core number of a node is the largest k such that the node is in a
+// k-core, a maximal subgraph where each node has in-degree plus out-degree
+// at least k within the subgraph.
+//
+// See also InCoreNumbers and OutCoreNumbers.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledDirected) CoreNumbers() []int {
+	a := g.LabeledAdjacencyList
+	t, _ := g.Transpose()
+	deg := g.InDegree()
+	for n, to := range a {
+		deg[n] += len(to)
+	}
+	return coreNumbers(deg, func(n NI, visit func(NI)) {
+		for _, to := range a[n] {
+			visit(to.To)
+		}
+		for _, fr := range t.LabeledAdjacencyList[n] {
+			visit(fr.To)
+		}
+	})
+}
+
 // AddNode maps a node in a supergraph to a subgraph node.
 //
 // Argument p must be an NI in supergraph s.Super.  AddNode panics if
@@ -753,6 +826,9 @@ func (g LabeledDirected) IsTree(root NI) (isTree, allTree bool) {
 //
 // Returned is the PageRank score for each node of g.
 //
+// See also PersonalizedPageRank which iterates to a convergence tolerance,
+// handles nodes with no out-arcs, and allows personalization.
+//
 // There are equivalent labeled and unlabeled versions of this method.
 func (g LabeledDirected) PageRank(d float64, n int) []float64 {
 	// Following "PageRank Explained" by Ian Rogers, accessed at
@@ -874,17 +950,17 @@ func (g LabeledDirected) Condensation() (scc [][]NI, cd AdjacencyList) {
 	})
 	cd = make(AdjacencyList, len(scc)) // return value
 	cond := make([]NI, len(a))         // mapping from g node to cd node
+	m := make([]int, len(scc))         // cn+1 for 'to' nodes listed for cn
 	for cn, c := range scc {
 		for _, n := range c {
 			cond[n] = NI(cn) // map g node to cd node
 		}
-		var tos []NI           // list of 'to' nodes
-		m := bits.New(len(cd)) // tos map
-		m.SetBit(cn, 1)
+		var tos []NI // list of 'to' nodes
+		m[cn] = cn + 1
 		for _, n := range c {
 			for _, to := range a[n] {
-				if ct := cond[to.To]; m.Bit(int(ct)) == 0 {
-					m.SetBit(int(ct), 1)
+				if ct := cond[to.To]; m[ct] != cn+1 {
+					m[ct] = cn + 1
 					tos = append(tos, ct)
 				}
 			}
@@ -47,6 +47,26 @@ func ExampleDirected_Balanced() {
 	// true
 }
 
+func ExampleDirected_CoreNumbers() {
+	// 0 <--> 1 <--> 2 <--> 0, 3 --> 0, and 4 --> 3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {0, 2},
+		2: {0, 1},
+		3: {0},
+		4: {3},
+	}}
+	fmt.Println("node:      0 1 2 3 4")
+	fmt.Println("in-core: ", g.InCoreNumbers())
+	fmt.Println("out-core:", g.OutCoreNumbers())
+	fmt.Println("core:    ", g.CoreNumbers())
+	// Output:
+	// node:      0 1 2 3 4
+	// in-core:  [2 2 2 0 0]
+	// out-core: [2 2 2 1 1]
+	// core:     [4 4 4 1 1]
+}
+
 func ExampleDirected_Cyclic() {
 	//   0
 	//  / \
@@ -6,6 +6,7 @@ package graph_test
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -861,3 +862,101 @@ func ExampleLabeledDirected_UnlabeledTranspose() {
 	// 2 []
 	// 2 arcs
 }
+
+// coreBF computes core numbers by repeatedly deleting nodes of degree less
+// than k for increasing k, where degree is computed by function deg on the
+// remaining nodes.
+func coreBF(n int, deg func(n int, in []bool) int) []int {
+	core := make([]int, n)
+	in := make([]bool, n)
+	for i := range in {
+		in[i] = true
+	}
+	left := n
+	for k := 1; left > 0; k++ {
+		for changed := true; changed; {
+			changed = false
+			for i := range in {
+				if in[i] && deg(i, in) < k {
+					in[i] = false
+					core[i] = k - 1
+					left--
+					changed = true
+				}
+			}
+		}
+	}
+	return core
+}
+
+func TestCoreNumbers(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+	for i := 0; i < 30; i++ {
+		n := 1 + r.Intn(15)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for j := r.Intn(4 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(r.Intn(n)))
+		}
+		inDeg := func(n int, in []bool) (d int) {
+			for fr, to := range g.AdjacencyList {
+				for _, to := range to {
+					if int(to) == n && in[fr] {
+						d++
+					}
+				}
+			}
+			return
+		}
+		outDeg := func(n int, in []bool) (d int) {
+			for _, to := range g.AdjacencyList[n] {
+				if in[to] {
+					d++
+				}
+			}
+			return
+		}
+		totDeg := func(n int, in []bool) int {
+			return inDeg(n, in) + outDeg(n, in)
+		}
+		for _, c := range []struct {
+			name string
+			got  []int
+			deg  func(int, []bool) int
+		}{
+			{"in", g.InCoreNumbers(), inDeg},
+			{"out", g.OutCoreNumbers(), outDeg},
+			{"total", g.CoreNumbers(), totDeg},
+		} {
+			if want := coreBF(n, c.deg); !reflect.DeepEqual(c.got, want) {
+				t.Fatal(c.name, g, c.got, want)
+			}
+		}
+		// undirected, where degree is len(g[n]), and consistent with
+		// DegeneracyOrdering
+		var u graph.Undirected
+		u.AdjacencyList = make(graph.AdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			u.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		got := u.CoreNumbers()
+		if want := coreBF(n, func(n int, in []bool) (d int) {
+			for _, to := range u.AdjacencyList[n] {
+				if in[to] {
+					d++
+				}
+			}
+			return
+		}); !reflect.DeepEqual(got, want) {
+			t.Fatal("undirected", u, got, want)
+		}
+		ord, breaks := u.DegeneracyOrdering()
+		for k, x := range breaks {
+			for _, n := range ord[:x] {
+				if got[n] < k {
+					t.Fatal("DegeneracyOrdering", u, got, ord, breaks)
+				}
+			}
+		}
+	}
+}
@@ -2,9 +2,11 @@
 // License MIT: http://opensource.org/licenses/MIT
 
 // Graph algorithms: Dijkstra, A*, Bellman Ford, Floyd Warshall;
-// Kruskal and Prim minimal spanning tree; topological sort and DAG longest
-// and shortest paths; Eulerian cycle and path; degeneracy and k-cores;
-// Bron Kerbosch clique finding; connected components; dominance; and others.
+// Kruskal and Prim minimal spanning tree; Edmonds minimum arborescence;
+// topological sort and DAG longest and shortest paths; Eulerian cycle and
+// path; degeneracy and k-cores; triangles and clustering; Bron Kerbosch
+// clique finding; connected components; dominance; betweenness and
+// closeness centrality; and others.
 //
 // This is a graph library of integer indexes.  To use it with application
 // data, you associate data with integer indexes, perform searches or other
@@ -119,4 +121,5 @@
 //  BellmanFord    Negative arc weights allowed, no negative cycles, all paths.
 //  DAGPath        O(n) algorithm for DAGs, arc weights of any sign.
 //  FloydWarshall  all pairs distances, no negative cycles.
+//  JumpPointPath  Uniform cost grids, single path.  See type Grid.
 package graph
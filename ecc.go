@@ -0,0 +1,368 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// ecc.go has exact diameter, radius, center, and periphery for large sparse
+// graphs.
+//
+// Computing all eccentricities takes a shortest path search from every
+// node.  The algorithms here instead bound eccentricities from relatively
+// few searches, typically a small number even for graphs of millions of
+// nodes.  Worst case time is still that of a search from every node.
+
+// Extremes holds the extreme eccentricities of a graph and nodes realizing
+// them.
+//
+// The eccentricity of a node is the greatest distance from the node to any
+// other node.  Diameter is the maximum eccentricity and Radius is the
+// minimum.  Center is the list of nodes with eccentricity Radius and
+// Periphery is the list of nodes with eccentricity Diameter, both in order
+// of node number.  The distance from N1 to N2 is Diameter.  Searches is the
+// number of shortest path searches done.
+type Extremes struct {
+	Diameter, Radius  int
+	Center, Periphery []NI
+	N1, N2            NI
+	Searches          int
+}
+
+// LabeledExtremes holds the extreme eccentricities of a graph with weighted
+// arcs and nodes realizing them.
+//
+// Fields are as described for Extremes except that distances are sums of
+// arc weights.
+type LabeledExtremes struct {
+	Diameter, Radius  float64
+	Center, Periphery []NI
+	N1, N2            NI
+	Searches          int
+}
+
+// boundExtremes computes extremes by the eccentricity bounding algorithm
+// of Takes and Kosters, extended to directed graphs.
+//
+// Func search must compute distances from node u in fwd and distances to
+// u in bwd, and return false if any node is unreachable in either
+// direction.  With the eccentricity e(u) of a searched node u, the
+// triangle inequality bounds the eccentricity of each node v as
+//
+//	max(bwd[v], e(u) - fwd[v]) <= e(v) <= bwd[v] + e(u)
+//
+// Nodes are searched until the eccentricity of each node is known or the
+// node is known not to be in the center or periphery.
+func boundExtremes(deg []int, search func(u NI, fwd, bwd []float64) bool) (x LabeledExtremes, ok bool) {
+	x.N1, x.N2 = -1, -1
+	n := len(deg)
+	if n == 0 {
+		return x, true
+	}
+	lo := make([]float64, n)
+	hi := make([]float64, n)
+	for v := range hi {
+		hi[v] = math.Inf(1)
+	}
+	fwd := make([]float64, n)
+	bwd := make([]float64, n)
+	searched := make([]bool, n)
+	dLo, rHi := 0., math.Inf(1) // bounds on diameter and radius
+	// search from u, returning the eccentricity of u and a farthest node
+	run := func(u NI) (e float64, far NI, ok bool) {
+		x.Searches++
+		if !search(u, fwd, bwd) {
+			return
+		}
+		far = u
+		for v, d := range fwd {
+			if d > e {
+				e, far = d, NI(v)
+			}
+		}
+		return e, far, true
+	}
+	// start with a node of max degree, as suggested by Takes and Kosters
+	u := NI(0)
+	for v, d := range deg {
+		if d > deg[u] {
+			u = NI(v)
+		}
+	}
+	for high := true; ; high = !high {
+		e, far, ok := run(u)
+		if !ok {
+			return x, false
+		}
+		searched[u] = true
+		if e > dLo {
+			dLo = e
+			x.N1, x.N2 = u, far
+		}
+		for v := range lo {
+			if l := math.Max(bwd[v], e-fwd[v]); l > lo[v] {
+				lo[v] = l
+			}
+			if h := bwd[v] + e; h < hi[v] {
+				hi[v] = h
+			}
+			if lo[v] > dLo {
+				dLo = lo[v]
+				x.N1, x.N2 = -1, -1 // not yet known
+			}
+			if hi[v] < rHi {
+				rHi = hi[v]
+			}
+		}
+		lo[u], hi[u] = e, e
+		// select next node to search, alternating between the greatest
+		// upper bound and the least lower bound.  ties go to higher degree.
+		u = -1
+		for v := range lo {
+			if searched[v] || lo[v] == hi[v] ||
+				hi[v] < dLo && lo[v] > rHi {
+				continue
+			}
+			switch {
+			case u < 0:
+			case high && (hi[v] > hi[u] || hi[v] == hi[u] && deg[v] > deg[u]):
+			case !high && (lo[v] < lo[u] || lo[v] == lo[u] && deg[v] > deg[u]):
+			default:
+				continue
+			}
+			u = NI(v)
+		}
+		if u < 0 {
+			break
+		}
+	}
+	x.Diameter, x.Radius = dLo, rHi
+	for v := range lo {
+		if lo[v] == hi[v] {
+			if lo[v] == rHi {
+				x.Center = append(x.Center, NI(v))
+			}
+			if lo[v] == dLo {
+				x.Periphery = append(x.Periphery, NI(v))
+			}
+		}
+	}
+	if x.N1 < 0 && len(x.Periphery) > 0 {
+		// diameter bound came from a lower bound.  search from a periphery
+		// node to find the other end.
+		x.N1 = x.Periphery[0]
+		_, x.N2, _ = run(x.N1)
+	}
+	return x, true
+}
+
+// bfsDist computes breadth-first distances from start in dist, returning
+// the number of nodes reached.
+func (g AdjacencyList) bfsDist(start NI, dist []float64) int {
+	for n := range dist {
+		dist[n] = -1
+	}
+	dist[start] = 0
+	frontier := []NI{start}
+	var next []NI
+	nReached := 1
+	for d := 1.; len(frontier) > 0; d++ {
+		next = next[:0]
+		for _, n := range frontier {
+			for _, to := range g[n] {
+				if dist[to] < 0 {
+					dist[to] = d
+					next = append(next, to)
+				}
+			}
+		}
+		nReached += len(next)
+		frontier, next = next, frontier
+	}
+	return nReached
+}
+
+func intExtremes(x LabeledExtremes) Extremes {
+	return Extremes{int(x.Diameter), int(x.Radius),
+		x.Center, x.Periphery, x.N1, x.N2, x.Searches}
+}
+
+// Extremes computes the diameter, radius, center, and periphery of a
+// connected undirected graph.
+//
+// The algorithm is the eccentricity bounding algorithm of Takes and
+// Kosters.  Breadth-first searches are done from selected nodes until the
+// bounds determine the result.  For large sparse graphs such as real world
+// networks, the number of searches is typically small.  Graphs with less
+// structure, such as uniform random graphs, can require many more searches
+// to determine all center and periphery nodes.
+//
+// If g is not connected, an error is returned.  If g has no nodes, the
+// zero Extremes is returned with N1 and N2 -1.
+//
+// See also Diameter.
+func (g Undirected) Extremes() (Extremes, error) {
+	a := g.AdjacencyList
+	deg := make([]int, len(a))
+	for n, to := range a {
+		deg[n] = len(to)
+	}
+	x, ok := boundExtremes(deg, func(u NI, fwd, bwd []float64) bool {
+		nr := a.bfsDist(u, fwd)
+		copy(bwd, fwd)
+		return nr == len(a)
+	})
+	if !ok {
+		return Extremes{}, errors.New("graph not connected")
+	}
+	return intExtremes(x), nil
+}
+
+// Extremes computes the diameter, radius, center, and periphery of a
+// strongly connected directed graph.
+//
+// The eccentricity of a node is the greatest distance from the node to any
+// other node, following arc directions.  The algorithm is the eccentricity
+// bounding algorithm of Takes and Kosters, extended to directed graphs by
+// searching from each selected node both forward and backward, through the
+// transpose of g.  Searches counts these pairs of searches.
+//
+// If g is not strongly connected, an error is returned.  If g has no nodes,
+// the zero Extremes is returned with N1 and N2 -1.
+func (g Directed) Extremes() (Extremes, error) {
+	a := g.AdjacencyList
+	t, _ := g.Transpose()
+	deg := t.InDegree() // out-degree of g
+	for n, to := range t.AdjacencyList {
+		deg[n] += len(to)
+	}
+	x, ok := boundExtremes(deg, func(u NI, fwd, bwd []float64) bool {
+		return a.bfsDist(u, fwd) == len(a) &&
+			t.AdjacencyList.bfsDist(u, bwd) == len(a)
+	})
+	if !ok {
+		return Extremes{}, errors.New("graph not strongly connected")
+	}
+	return intExtremes(x), nil
+}
+
+// Extremes computes the diameter, radius, center, and periphery of a
+// strongly connected graph with weighted arcs.
+//
+// Arc weights are given by WeightFunc w and must be non-negative.  The
+// graph may be directed or undirected.  Distances are computed with
+// Dijkstra's algorithm, forward and backward through the transpose of g.
+// Otherwise the method is the same as Directed.Extremes.
+//
+// Eccentricities equal within floating point rounding may be reported as
+// different.  Results are exact for weights with exact sums such as
+// small integers.
+func (g LabeledAdjacencyList) Extremes(w WeightFunc) (LabeledExtremes, error) {
+	t, _ := LabeledDirected{g}.Transpose()
+	deg := make([]int, len(g))
+	for n, to := range g {
+		deg[n] += len(to)
+		for _, h := range to {
+			deg[h.To]++
+		}
+	}
+	x, ok := boundExtremes(deg, func(u NI, fwd, bwd []float64) bool {
+		_, _, d, nf := g.Dijkstra(u, -1, w)
+		copy(fwd, d)
+		_, _, d, nb := t.LabeledAdjacencyList.Dijkstra(u, -1, w)
+		copy(bwd, d)
+		return nf == len(g) && nb == len(g)
+	})
+	if !ok {
+		return LabeledExtremes{}, errors.New("graph not strongly connected")
+	}
+	return x, nil
+}
+
+// Diameter computes the diameter of a connected undirected graph and end
+// nodes of a shortest path realizing the diameter.
+//
+// The algorithm is iFUB of Crescenzi et al., starting from a node found
+// with the 4-sweep heuristic.  Eccentricities are computed for nodes in
+// order of decreasing distance from this node until the greatest
+// eccentricity found exceeds what remaining nodes could have.  For large
+// sparse graphs, the number of breadth-first searches is typically small.
+//
+// If g is not connected, an error is returned.  If g has no nodes, Diameter
+// returns 0, -1, -1, nil.
+//
+// See also Extremes, which also finds the radius, center, and periphery.
+func (g Undirected) Diameter() (d int, n1, n2 NI, err error) {
+	a := g.AdjacencyList
+	if len(a) == 0 {
+		return 0, -1, -1, nil
+	}
+	dist := make([]float64, len(a))
+	// ecc searches from u, returning the eccentricity of u and a farthest
+	// node, leaving distances from u in dist.
+	ecc := func(u NI) (e int, far NI) {
+		a.bfsDist(u, dist)
+		far = u
+		for v, dv := range dist {
+			if int(dv) > e {
+				e, far = int(dv), NI(v)
+			}
+		}
+		return
+	}
+	// mid returns a node halfway on a shortest path from u to v where
+	// dist holds distances from u.
+	mid := func(v NI) NI {
+		for h := int(dist[v]) / 2; int(dist[v]) > h; {
+			for _, to := range a[v] {
+				if dist[to] == dist[v]-1 {
+					v = to
+					break
+				}
+			}
+		}
+		return v
+	}
+	// 4-sweep
+	r1 := NI(0)
+	for n, to := range a {
+		if len(to) > len(a[r1]) {
+			r1 = NI(n)
+		}
+	}
+	if a.bfsDist(r1, dist) < len(a) {
+		return 0, -1, -1, errors.New("graph not connected")
+	}
+	update := func(e int, u, far NI) {
+		if e > d {
+			d, n1, n2 = e, u, far
+		}
+	}
+	n1, n2 = r1, r1
+	_, a1 := ecc(r1)
+	e, b1 := ecc(a1)
+	update(e, a1, b1)
+	_, a2 := ecc(mid(b1))
+	e, b2 := ecc(a2)
+	update(e, a2, b2)
+	u := mid(b2)
+	// iFUB.  nodes at distance less than i from u are at distance at most
+	// 2(i-1) from each other, so the search can stop when the diameter
+	// found is at least that.
+	eu, fu := ecc(u)
+	update(eu, u, fu)
+	fringe := make([][]NI, eu+1)
+	for v, dv := range dist {
+		fringe[int(dv)] = append(fringe[int(dv)], NI(v))
+	}
+	for i := eu; i > 0 && d < 2*i; i-- {
+		for _, v := range fringe[i] {
+			e, far := ecc(v)
+			update(e, v, far)
+		}
+	}
+	return
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Extremes() {
+	// 0--1--2--3--4
+	//       |
+	//       5
+	var g graph.Undirected
+	for _, e := range []graph.Edge{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {2, 5}} {
+		g.AddEdge(e.N1, e.N2)
+	}
+	x, err := g.Extremes()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("diameter:", x.Diameter, "from", x.N1, "to", x.N2)
+	fmt.Println("radius:", x.Radius)
+	fmt.Println("center:", x.Center)
+	fmt.Println("periphery:", x.Periphery)
+	// Output:
+	// diameter: 4 from 0 to 4
+	// radius: 2
+	// center: [2]
+	// periphery: [0 4]
+}
+
+func ExampleUndirected_Diameter() {
+	// 0--1--2--3--4
+	//       |
+	//       5
+	var g graph.Undirected
+	for _, e := range []graph.Edge{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {2, 5}} {
+		g.AddEdge(e.N1, e.N2)
+	}
+	fmt.Println(g.Diameter())
+	// Output:
+	// 4 0 4 <nil>
+}
+
+func ExampleDirected_Extremes() {
+	// 0->1->2->3->0, 2->0
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3, 0},
+		3: {0},
+	}}
+	x, err := g.Extremes()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("diameter:", x.Diameter, "from", x.N1, "to", x.N2)
+	fmt.Println("radius:", x.Radius)
+	fmt.Println("center:", x.Center)
+	fmt.Println("periphery:", x.Periphery)
+	// Output:
+	// diameter: 3 from 0 to 3
+	// radius: 2
+	// center: [1 2]
+	// periphery: [0 3]
+}
+
+func ExampleLabeledAdjacencyList_Extremes() {
+	// undirected path 0--1--2 with weights 3 and 1
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	x, err := g.LabeledAdjacencyList.Extremes(func(l graph.LI) float64 {
+		return float64(l)
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("diameter:", x.Diameter)
+	fmt.Println("radius:", x.Radius)
+	fmt.Println("center:", x.Center)
+	fmt.Println("periphery:", x.Periphery)
+	// Output:
+	// diameter: 4
+	// radius: 3
+	// center: [1]
+	// periphery: [0 2]
+}
+
+// eccBF computes all eccentricities with a search from every node,
+// returning nil if any node is unreachable.
+func eccBF(g graph.LabeledAdjacencyList, w graph.WeightFunc) []float64 {
+	ecc := make([]float64, len(g))
+	for n := range g {
+		_, _, d, nr := g.Dijkstra(graph.NI(n), -1, w)
+		if nr != len(g) {
+			return nil
+		}
+		for _, d := range d {
+			if d > ecc[n] {
+				ecc[n] = d
+			}
+		}
+	}
+	return ecc
+}
+
+// extremesBF returns diameter, radius, center, and periphery from
+// eccentricities.
+func extremesBF(ecc []float64) (d, r float64, c, p []graph.NI) {
+	r = ecc[0]
+	for _, e := range ecc {
+		if e > d {
+			d = e
+		}
+		if e < r {
+			r = e
+		}
+	}
+	for n, e := range ecc {
+		if e == r {
+			c = append(c, graph.NI(n))
+		}
+		if e == d {
+			p = append(p, graph.NI(n))
+		}
+	}
+	return
+}
+
+func TestExtremes(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	unit := func(graph.LI) float64 { return 1 }
+	wt := func(l graph.LI) float64 { return float64(l) }
+	for tc := 0; tc < 300; tc++ {
+		n := 1 + r.Intn(30)
+		m := n - 1 + r.Intn(2*n)
+		directed := tc%3 == 1
+		var u graph.AdjacencyList
+		if directed {
+			u = graph.GnmDirected(n, m, r).AdjacencyList
+		} else {
+			u = graph.GnmUndirected(n, m, r).AdjacencyList
+		}
+		g := make(graph.LabeledAdjacencyList, n)
+		for fr, to := range u {
+			for _, to := range to {
+				g[fr] = append(g[fr], graph.Half{To: to})
+			}
+		}
+		// random integer weights 1-5, the same for both arcs of an edge
+		for fr, to := range g {
+			for x, h := range to {
+				if !directed && h.To < graph.NI(fr) {
+					continue
+				}
+				l := graph.LI(1 + r.Intn(5))
+				to[x].Label = l
+				if !directed {
+					for y, b := range g[h.To] {
+						if b.To == graph.NI(fr) {
+							g[h.To][y].Label = l
+							break
+						}
+					}
+				}
+			}
+		}
+		ecc := eccBF(g, unit)
+		var x graph.Extremes
+		var err error
+		if directed {
+			x, err = graph.Directed{g.Unlabeled()}.Extremes()
+		} else {
+			x, err = graph.Undirected{g.Unlabeled()}.Extremes()
+		}
+		if ecc == nil {
+			if err == nil {
+				t.Fatal("no error for graph not connected")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, rad, c, p := extremesBF(ecc)
+		if x.Diameter != int(d) || x.Radius != int(rad) ||
+			!reflect.DeepEqual(x.Center, c) ||
+			!reflect.DeepEqual(x.Periphery, p) {
+			t.Fatalf("got %+v, want d %v r %v c %v p %v", x, d, rad, c, p)
+		}
+		_, _, dist, _ := g.Dijkstra(x.N1, -1, unit)
+		if dist[x.N2] != d {
+			t.Fatal("N1 N2 distance", dist[x.N2], "want", d)
+		}
+		if !directed {
+			dd, n1, n2, err := graph.Undirected{g.Unlabeled()}.Diameter()
+			if err != nil || dd != int(d) {
+				t.Fatal("Diameter", dd, err, "want", d)
+			}
+			_, _, dist, _ := g.Dijkstra(n1, -1, unit)
+			if dist[n2] != d {
+				t.Fatal("Diameter ends distance", dist[n2], "want", d)
+			}
+		}
+		// weighted
+		wx, err := g.Extremes(wt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, rad, c, p = extremesBF(eccBF(g, wt))
+		if wx.Diameter != d || wx.Radius != rad ||
+			!reflect.DeepEqual(wx.Center, c) ||
+			!reflect.DeepEqual(wx.Periphery, p) {
+			t.Fatalf("weighted got %+v, want d %v r %v c %v p %v",
+				wx, d, rad, c, p)
+		}
+		_, _, dist, _ = g.Dijkstra(wx.N1, -1, wt)
+		if dist[wx.N2] != d {
+			t.Fatal("weighted N1 N2 distance", dist[wx.N2], "want", d)
+		}
+	}
+}
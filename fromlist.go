@@ -84,6 +84,9 @@ func (f FromList) BoundsOk() (ok bool, n NI) {
 //
 // The method relies on populated PathEnd.Len members.  Use RecalcLen if
 // the Len members are not known to be present and correct.
+//
+// CommonStart walks paths back toward the root on each call.  For many
+// queries on the same FromList, see BinaryLifting and EulerTour.
 func (f FromList) CommonStart(a, b NI) NI {
 	p := f.Paths
 	if p[a].Len < p[b].Len {
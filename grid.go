@@ -0,0 +1,437 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/soniakeys/bits"
+)
+
+// grid.go has the Grid type, constructors for grid graphs, and jump point
+// search.
+
+// Grid describes a two dimensional grid of cells, possibly with obstacles.
+//
+// Cells are addressed with x, y coordinates where 0 <= x < Width and
+// 0 <= y < Height.  Each cell corresponds to a node of a grid graph, with
+// node numbers assigned in row major order.  See methods Node and XY for
+// the mapping.
+//
+// If Diagonal is false, grid graphs have 4-connectivity, with edges only
+// between orthogonally adjacent cells.  If Diagonal is true, grid graphs have
+// 8-connectivity, additionally with edges between diagonally adjacent cells.
+// A diagonal edge is present only when both orthogonal cells it passes
+// are open.  That is, diagonal edges do not "cut corners."
+//
+// Member Blocked is a bitmap of obstacle cells, indexed by node number.
+// Blocked cells are still nodes of grid graphs but they are isolated nodes,
+// with no edges.  Blocked may be a zero value to indicate no obstacles.
+// Otherwise it must have length Width * Height.
+//
+// Construct a Grid with a Go struct literal.
+type Grid struct {
+	Width, Height int
+	Diagonal      bool
+	Blocked       bits.Bits
+}
+
+// Labels of grid graph edges, as constructed by Grid.LabeledUndirected.
+const (
+	GridOrthogonal LI = 0 // edge between orthogonally adjacent cells
+	GridDiagonal   LI = 1 // edge between diagonally adjacent cells
+)
+
+// GridWeight is a WeightFunc for grid graphs.
+//
+// It returns 1 for label GridOrthogonal and Sqrt2 for label GridDiagonal.
+func GridWeight(l LI) float64 {
+	if l == GridDiagonal {
+		return math.Sqrt2
+	}
+	return 1
+}
+
+// Node returns the node number of the cell at x, y.
+func (g Grid) Node(x, y int) NI {
+	return NI(y*g.Width + x)
+}
+
+// XY returns the coordinates of the cell corresponding to node n.
+func (g Grid) XY(n NI) (x, y int) {
+	return int(n) % g.Width, int(n) / g.Width
+}
+
+// Open returns true if x, y is within the grid and not blocked.
+func (g Grid) Open(x, y int) bool {
+	if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+		return false
+	}
+	return g.Blocked.Num == 0 || g.Blocked.Bit(y*g.Width+x) == 0
+}
+
+// canStep returns true if there is an edge from x, y to x+dx, y+dy.
+//
+// x, y must be open.
+func (g Grid) canStep(x, y, dx, dy int) bool {
+	if !g.Open(x+dx, y+dy) {
+		return false
+	}
+	return dx == 0 || dy == 0 || g.Open(x+dx, y) && g.Open(x, y+dy)
+}
+
+// grid directions, orthogonal then diagonal.
+var gridDir = [8]struct{ dx, dy int }{
+	{1, 0}, {0, 1}, {-1, 0}, {0, -1},
+	{1, 1}, {-1, 1}, {-1, -1}, {1, -1},
+}
+
+// directions used for the connectivity of g
+func (g Grid) dirs() []struct{ dx, dy int } {
+	if g.Diagonal {
+		return gridDir[:]
+	}
+	return gridDir[:4]
+}
+
+// Undirected constructs the grid graph of g.
+//
+// The order of the returned graph is g.Width * g.Height.
+//
+// See also LabeledUndirected.
+func (g Grid) Undirected() Undirected {
+	a := make(AdjacencyList, g.Width*g.Height)
+	dirs := g.dirs()
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if !g.Open(x, y) {
+				continue
+			}
+			var to []NI
+			for _, d := range dirs {
+				if g.canStep(x, y, d.dx, d.dy) {
+					to = append(to, g.Node(x+d.dx, y+d.dy))
+				}
+			}
+			a[g.Node(x, y)] = to
+		}
+	}
+	return Undirected{a}
+}
+
+// LabeledUndirected constructs the grid graph of g with labeled edges.
+//
+// Edges are labeled GridOrthogonal or GridDiagonal.  Also returned is
+// WeightFunc GridWeight, giving edge weights as Euclidean distances between
+// cell centers.
+//
+// See also Undirected.
+func (g Grid) LabeledUndirected() (u LabeledUndirected, w WeightFunc) {
+	a := make(LabeledAdjacencyList, g.Width*g.Height)
+	dirs := g.dirs()
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if !g.Open(x, y) {
+				continue
+			}
+			var to []Half
+			for _, d := range dirs {
+				if g.canStep(x, y, d.dx, d.dy) {
+					l := GridOrthogonal
+					if d.dx != 0 && d.dy != 0 {
+						l = GridDiagonal
+					}
+					to = append(to, Half{g.Node(x+d.dx, y+d.dy), l})
+				}
+			}
+			a[g.Node(x, y)] = to
+		}
+	}
+	return LabeledUndirected{a}, GridWeight
+}
+
+// Heuristic returns a heuristic for searches to the given end node.
+//
+// For a grid without diagonal connectivity the heuristic is Manhattan
+// distance.  For a grid with diagonal connectivity it is octile distance.
+// Either way it is admissible and monotonic for the graph returned by
+// LabeledUndirected with weights GridWeight.
+func (g Grid) Heuristic(end NI) Heuristic {
+	ex, ey := g.XY(end)
+	if !g.Diagonal {
+		return func(n NI) float64 {
+			x, y := g.XY(n)
+			return float64(iabs(x-ex) + iabs(y-ey))
+		}
+	}
+	return func(n NI) float64 {
+		x, y := g.XY(n)
+		return octile(x-ex, y-ey)
+	}
+}
+
+func iabs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func isign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+	return 0
+}
+
+func octile(dx, dy int) float64 {
+	dx = iabs(dx)
+	dy = iabs(dy)
+	if dx < dy {
+		dx, dy = dy, dx
+	}
+	return float64(dx-dy) + float64(dy)*math.Sqrt2
+}
+
+// JumpPointPath finds a shortest path between cells of a grid.
+//
+// The algorithm is jump point search, by Harabor and Grastien.  It finds
+// the same distance paths as AStarAPath or AStarMPath on the graph returned
+// by g.LabeledUndirected, but by exploiting symmetries of uniform cost grids
+// it typically expands far fewer nodes.  It works directly on the Grid and
+// does not need a graph to be constructed.
+//
+// For grids with diagonal connectivity, the "no corner cutting" rule
+// of type Grid applies.  For grids without diagonal connectivity the
+// four-connected variant of jump point search is used.
+//
+// The returned path is the full path of cells, labeled as by
+// g.LabeledUndirected, and the total path distance as by GridWeight.
+// If no path is found, the Path member of the returned LabeledPath is nil
+// and the distance is 0.
+func (g Grid) JumpPointPath(start, end NI) (LabeledPath, float64) {
+	sx, sy := g.XY(start)
+	ex, ey := g.XY(end)
+	if !g.Open(sx, sy) || !g.Open(ex, ey) {
+		return LabeledPath{end, nil}, 0
+	}
+	nNodes := g.Width * g.Height
+	// A* over jump points, much as AStarM.
+	h := g.Heuristic(end)
+	d := make([]float64, nNodes)
+	from := make([]NI, nNodes)
+	r := make([]rNode, nNodes)
+	for i := range r {
+		r[i].nx = NI(i)
+	}
+	from[start] = -1
+	cr := &r[start]
+	cr.state = open
+	cr.f = h(start)
+	oh := openHeap{cr}
+	var nbs []struct{ dx, dy int }
+	for len(oh) > 0 {
+		bestPath := heap.Pop(&oh).(*rNode)
+		bestNode := bestPath.nx
+		if bestNode == end {
+			p := g.jpExpand(from, end)
+			return p, p.Distance(GridWeight)
+		}
+		bestPath.state = closed
+		x, y := g.XY(bestNode)
+		nbs = g.jpNeighbors(x, y, from[bestNode], nbs[:0])
+		for _, nb := range nbs {
+			jx, jy, ok := g.jump(x+nb.dx, y+nb.dy, nb.dx, nb.dy, ex, ey)
+			if !ok {
+				continue
+			}
+			j := g.Node(jx, jy)
+			alt := &r[j]
+			if alt.state == closed {
+				continue
+			}
+			var dj float64
+			if g.Diagonal {
+				dj = d[bestNode] + octile(jx-x, jy-y)
+			} else {
+				dj = d[bestNode] + float64(iabs(jx-x)+iabs(jy-y))
+			}
+			if alt.state == open && dj >= d[j] {
+				continue
+			}
+			d[j] = dj
+			from[j] = bestNode
+			alt.f = dj + h(j)
+			if alt.state == open {
+				heap.Fix(&oh, alt.fx)
+			} else {
+				alt.state = open
+				heap.Push(&oh, alt)
+			}
+		}
+	}
+	return LabeledPath{end, nil}, 0
+}
+
+// jpNeighbors returns the pruned neighbor directions of cell x, y reached
+// from jump point parent.  Results are appended to nbs.
+func (g Grid) jpNeighbors(x, y int, parent NI, nbs []struct{ dx, dy int }) []struct{ dx, dy int } {
+	add := func(dx, dy int) {
+		nbs = append(nbs, struct{ dx, dy int }{dx, dy})
+	}
+	if parent < 0 {
+		for _, d := range g.dirs() {
+			if g.canStep(x, y, d.dx, d.dy) {
+				add(d.dx, d.dy)
+			}
+		}
+		return nbs
+	}
+	px, py := g.XY(parent)
+	dx := isign(x - px)
+	dy := isign(y - py)
+	switch {
+	case !g.Diagonal:
+		if dx != 0 {
+			add(0, -1)
+			add(0, 1)
+			add(dx, 0)
+		} else {
+			add(-1, 0)
+			add(1, 0)
+			add(0, dy)
+		}
+	case dx != 0 && dy != 0:
+		v := g.Open(x, y+dy)
+		h := g.Open(x+dx, y)
+		if v {
+			add(0, dy)
+		}
+		if h {
+			add(dx, 0)
+		}
+		if v && h {
+			add(dx, dy)
+		}
+	case dx != 0:
+		next := g.Open(x+dx, y)
+		up := g.Open(x, y-1)
+		down := g.Open(x, y+1)
+		if next {
+			add(dx, 0)
+			if up {
+				add(dx, -1)
+			}
+			if down {
+				add(dx, 1)
+			}
+		}
+		if up {
+			add(0, -1)
+		}
+		if down {
+			add(0, 1)
+		}
+	default:
+		next := g.Open(x, y+dy)
+		left := g.Open(x-1, y)
+		right := g.Open(x+1, y)
+		if next {
+			add(0, dy)
+			if left {
+				add(-1, dy)
+			}
+			if right {
+				add(1, dy)
+			}
+		}
+		if left {
+			add(-1, 0)
+		}
+		if right {
+			add(1, 0)
+		}
+	}
+	return nbs
+}
+
+// jump scans from cell x, y in direction dx, dy for the next jump point.
+//
+// Cell x, y is the cell entered by a step in direction dx, dy.
+// Return value ok is false if the scan runs into an obstacle or the grid
+// boundary without finding a jump point.
+func (g Grid) jump(x, y, dx, dy, ex, ey int) (jx, jy int, ok bool) {
+	for {
+		if !g.Open(x, y) {
+			return
+		}
+		if x == ex && y == ey {
+			return x, y, true
+		}
+		switch {
+		case dx != 0 && dy != 0:
+			if _, _, ok = g.jump(x+dx, y, dx, 0, ex, ey); ok {
+				return x, y, true
+			}
+			if _, _, ok = g.jump(x, y+dy, 0, dy, ex, ey); ok {
+				return x, y, true
+			}
+			if !g.Open(x+dx, y) || !g.Open(x, y+dy) {
+				return // diagonal step would cut a corner
+			}
+		case dx != 0:
+			if g.Open(x, y-1) && !g.Open(x-dx, y-1) ||
+				g.Open(x, y+1) && !g.Open(x-dx, y+1) {
+				return x, y, true
+			}
+		default:
+			if g.Open(x-1, y) && !g.Open(x-1, y-dy) ||
+				g.Open(x+1, y) && !g.Open(x+1, y-dy) {
+				return x, y, true
+			}
+			if !g.Diagonal {
+				// four-connected: moving vertically, check for
+				// horizontal jump points
+				if _, _, ok = g.jump(x+1, y, 1, 0, ex, ey); ok {
+					return x, y, true
+				}
+				if _, _, ok = g.jump(x-1, y, -1, 0, ex, ey); ok {
+					return x, y, true
+				}
+			}
+		}
+		x += dx
+		y += dy
+	}
+}
+
+// jpExpand expands the jump point path to end into a full cell path.
+func (g Grid) jpExpand(from []NI, end NI) LabeledPath {
+	var jp []NI
+	for n := end; n >= 0; n = from[n] {
+		jp = append(jp, n)
+	}
+	start := jp[len(jp)-1]
+	var p []Half
+	x, y := g.XY(start)
+	for i := len(jp) - 2; i >= 0; i-- {
+		jx, jy := g.XY(jp[i])
+		dx := isign(jx - x)
+		dy := isign(jy - y)
+		l := GridOrthogonal
+		if dx != 0 && dy != 0 {
+			l = GridDiagonal
+		}
+		for x != jx || y != jy {
+			x += dx
+			y += dy
+			p = append(p, Half{g.Node(x, y), l})
+		}
+	}
+	return LabeledPath{start, p}
+}
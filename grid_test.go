@@ -0,0 +1,134 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/bits"
+	"github.com/soniakeys/graph"
+)
+
+func ExampleGrid_Undirected() {
+	// 0 1 2
+	// 3 # 5
+	blocked := bits.New(6)
+	blocked.SetBit(4, 1)
+	g := graph.Grid{Width: 3, Height: 2, Blocked: blocked}
+	u := g.Undirected()
+	for n, to := range u.AdjacencyList {
+		x, y := g.XY(graph.NI(n))
+		fmt.Printf("%d (%d, %d) %v\n", n, x, y, to)
+	}
+	// Output:
+	// 0 (0, 0) [1 3]
+	// 1 (1, 0) [2 0]
+	// 2 (2, 0) [5 1]
+	// 3 (0, 1) [0]
+	// 4 (1, 1) []
+	// 5 (2, 1) [2]
+}
+
+func ExampleGrid_LabeledUndirected() {
+	// 0 1 2
+	// 3 4 #
+	blocked := bits.New(6)
+	blocked.SetBit(5, 1)
+	g := graph.Grid{Width: 3, Height: 2, Diagonal: true, Blocked: blocked}
+	u, w := g.LabeledUndirected()
+	for n, to := range u.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Printf("%.3f\n", w(graph.GridDiagonal))
+	// Output:
+	// 0 [{1 0} {3 0} {4 1}]
+	// 1 [{2 0} {4 0} {0 0} {3 1}]
+	// 2 [{1 0}]
+	// 3 [{4 0} {0 0} {1 1}]
+	// 4 [{3 0} {1 0} {0 1}]
+	// 5 []
+	// 1.414
+}
+
+func ExampleGrid_Heuristic() {
+	g := graph.Grid{Width: 5, Height: 5}
+	h := g.Heuristic(g.Node(4, 4))
+	fmt.Println(h(g.Node(1, 2)))
+	g.Diagonal = true
+	h = g.Heuristic(g.Node(4, 4))
+	fmt.Printf("%.3f\n", h(g.Node(1, 2)))
+	// Output:
+	// 5
+	// 3.828
+}
+
+func ExampleGrid_JumpPointPath() {
+	// . . . . .
+	// . # # # .
+	// . . . # .
+	// S # . # E
+	blocked := bits.New(20)
+	for _, n := range []int{6, 7, 8, 13, 16, 18} {
+		blocked.SetBit(n, 1)
+	}
+	g := graph.Grid{Width: 5, Height: 4, Diagonal: true, Blocked: blocked}
+	p, d := g.JumpPointPath(g.Node(0, 3), g.Node(4, 3))
+	x, y := g.XY(p.Start)
+	fmt.Printf("(%d,%d)", x, y)
+	for _, h := range p.Path {
+		x, y = g.XY(h.To)
+		fmt.Printf(" (%d,%d)", x, y)
+	}
+	fmt.Println()
+	fmt.Printf("distance %.3f\n", d)
+	// Output:
+	// (0,3) (0,2) (0,1) (0,0) (1,0) (2,0) (3,0) (4,0) (4,1) (4,2) (4,3)
+	// distance 10.000
+}
+
+func TestJumpPointPath(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for _, diag := range []bool{false, true} {
+		for i := 0; i < 200; i++ {
+			w := 1 + r.Intn(20)
+			h := 1 + r.Intn(20)
+			b := bits.New(w * h)
+			density := r.Float64() * .4
+			for n := 0; n < w*h; n++ {
+				if r.Float64() < density {
+					b.SetBit(n, 1)
+				}
+			}
+			g := graph.Grid{Width: w, Height: h, Diagonal: diag, Blocked: b}
+			u, wf := g.LabeledUndirected()
+			start := graph.NI(r.Intn(w * h))
+			end := graph.NI(r.Intn(w * h))
+			pa, da := u.AStarAPath(start, end, g.Heuristic(end), wf)
+			pj, dj := g.JumpPointPath(start, end)
+			if (pa.Path == nil) != (pj.Path == nil) {
+				t.Fatal("path found mismatch", diag, w, h, start, end)
+			}
+			if math.Abs(da-dj) > 1e-9 {
+				t.Fatal("distance mismatch", diag, w, h, start, end, da, dj)
+			}
+			if pj.Path == nil {
+				continue
+			}
+			// validate path
+			if pj.Start != start || pj.Path[len(pj.Path)-1].To != end {
+				t.Fatal("invalid path ends", pj)
+			}
+			fr := start
+			for _, hf := range pj.Path {
+				if ok, _ := u.HasArc(fr, hf.To); !ok {
+					t.Fatal("invalid step", fr, hf.To)
+				}
+				fr = hf.To
+			}
+		}
+	}
+}
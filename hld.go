@@ -0,0 +1,219 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// HeavyLight is a heavy-light decomposition of a tree or forest.
+//
+// The decomposition assigns each node a position in 0..n-1 such that each
+// subtree occupies a contiguous range of positions and any tree path is
+// covered by O(log n) contiguous ranges.  Per-node values can then be held
+// in position order in a SegmentTree to support path and subtree aggregate
+// queries and updates in O(log² n) and O(log n) time respectively.
+//
+// Construct with FromList.HeavyLight.
+type HeavyLight struct {
+	from  []NI
+	depth []int
+	head  []NI  // top node of the heavy chain containing each node
+	pos   []int // position of each node
+	node  []NI  // node at each position
+	size  []int // subtree size of each node
+}
+
+// PosRange is a half-open range [Lo, Hi) of HeavyLight positions.
+//
+// See HeavyLight.PathRanges.
+type PosRange struct {
+	Lo, Hi int
+	Up     bool // true if path order is decreasing position
+}
+
+// HeavyLight constructs a heavy-light decomposition of f.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+// It can represent a forest.
+func (f FromList) HeavyLight() *HeavyLight {
+	p := f.Paths
+	h := &HeavyLight{
+		from:  make([]NI, len(p)),
+		depth: depths(p),
+		head:  make([]NI, len(p)),
+		pos:   make([]int, len(p)),
+		node:  make([]NI, len(p)),
+		size:  f.SubtreeSizes(),
+	}
+	for n, e := range p {
+		h.from[n] = e.From
+	}
+	ch, _ := f.Transpose(nil)
+	// Preorder visiting the heavy child first.  Pushing it last on the
+	// stack makes it the next node popped.
+	pos := 0
+	var stack []NI
+	for r, e := range p {
+		if e.From >= 0 {
+			continue
+		}
+		h.head[r] = NI(r)
+		stack = append(stack[:0], NI(r))
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			h.pos[n] = pos
+			h.node[pos] = n
+			pos++
+			to := ch.AdjacencyList[n]
+			if len(to) == 0 {
+				continue
+			}
+			heavy := to[0]
+			for _, c := range to[1:] {
+				if h.size[c] > h.size[heavy] {
+					heavy = c
+				}
+			}
+			for _, c := range to {
+				if c != heavy {
+					h.head[c] = c
+					stack = append(stack, c)
+				}
+			}
+			h.head[heavy] = h.head[n]
+			stack = append(stack, heavy)
+		}
+	}
+	return h
+}
+
+// Pos returns the position of node n.
+func (h *HeavyLight) Pos(n NI) int {
+	return h.pos[n]
+}
+
+// Node returns the node at position pos.
+func (h *HeavyLight) Node(pos int) NI {
+	return h.node[pos]
+}
+
+// Subtree returns the range of positions of the subtree rooted at n.
+//
+// Node n is at position lo.
+func (h *HeavyLight) Subtree(n NI) (lo, hi int) {
+	lo = h.pos[n]
+	return lo, lo + h.size[n]
+}
+
+// LCA returns the lowest common ancestor of nodes a and b.
+//
+// LCA returns -1 if a and b are in different trees of a forest.
+//
+// The result is the same as FromList.CommonStart.
+func (h *HeavyLight) LCA(a, b NI) NI {
+	for h.head[a] != h.head[b] {
+		if h.depth[h.head[a]] < h.depth[h.head[b]] {
+			a, b = b, a
+		}
+		if a = h.from[h.head[a]]; a < 0 {
+			return -1
+		}
+	}
+	if h.depth[a] < h.depth[b] {
+		return a
+	}
+	return b
+}
+
+// PathRanges returns ranges of positions covering the nodes of the tree path
+// from a to b, inclusive.
+//
+// Ranges are returned in path order from a to b.  Positions increase going
+// down a tree, so ranges on the path from a up to the lowest common ancestor
+// of a and b are traversed in decreasing position order and have Up = true.
+// The remaining ranges, from the common ancestor down to b, have Up = false.
+//
+// PathRanges returns nil if a and b are in different trees of a forest.
+func (h *HeavyLight) PathRanges(a, b NI) []PosRange {
+	var up, down []PosRange
+	for h.head[a] != h.head[b] {
+		if h.depth[h.head[a]] >= h.depth[h.head[b]] {
+			up = append(up, PosRange{h.pos[h.head[a]], h.pos[a] + 1, true})
+			a = h.from[h.head[a]]
+		} else {
+			down = append(down, PosRange{h.pos[h.head[b]], h.pos[b] + 1, false})
+			b = h.from[h.head[b]]
+		}
+		if a < 0 || b < 0 {
+			return nil
+		}
+	}
+	if h.pos[a] > h.pos[b] {
+		up = append(up, PosRange{h.pos[b], h.pos[a] + 1, true})
+	} else {
+		down = append(down, PosRange{h.pos[a], h.pos[b] + 1, false})
+	}
+	for i := len(down) - 1; i >= 0; i-- {
+		up = append(up, down[i])
+	}
+	return up
+}
+
+// SegmentTree constructs a SegmentTree holding per-node values in position
+// order.
+//
+// Argument values is indexed by node.
+func (h *HeavyLight) SegmentTree(values []float64, agg Aggregate) *SegmentTree {
+	v := make([]float64, len(values))
+	for n, x := range values {
+		v[h.pos[n]] = x
+	}
+	return NewSegmentTree(v, agg)
+}
+
+// PathQuery returns the aggregate of values in s of the nodes on the
+// tree path from a to b, inclusive.
+//
+// Values are combined in path order, so the Combine function of the
+// Aggregate need only be associative.  SegmentTree s must have been
+// constructed with h.SegmentTree.
+//
+// PathQuery returns the Aggregate Identity if a and b are in different
+// trees of a forest.
+func (h *HeavyLight) PathQuery(s *SegmentTree, a, b NI) float64 {
+	r := s.agg.Identity
+	for _, pr := range h.PathRanges(a, b) {
+		if pr.Up {
+			r = s.agg.Combine(r, s.QueryReverse(pr.Lo, pr.Hi))
+		} else {
+			r = s.agg.Combine(r, s.Query(pr.Lo, pr.Hi))
+		}
+	}
+	return r
+}
+
+// PathApply applies update u to the values in s of the nodes on the tree
+// path from a to b, inclusive.
+//
+// SegmentTree s must have been constructed with h.SegmentTree.
+func (h *HeavyLight) PathApply(s *SegmentTree, a, b NI, u float64) {
+	for _, pr := range h.PathRanges(a, b) {
+		s.Apply(pr.Lo, pr.Hi, u)
+	}
+}
+
+// SubtreeQuery returns the aggregate of values in s of the nodes in the
+// subtree rooted at n.
+//
+// SegmentTree s must have been constructed with h.SegmentTree.
+func (h *HeavyLight) SubtreeQuery(s *SegmentTree, n NI) float64 {
+	return s.Query(h.Subtree(n))
+}
+
+// SubtreeApply applies update u to the values in s of the nodes in the
+// subtree rooted at n.
+//
+// SegmentTree s must have been constructed with h.SegmentTree.
+func (h *HeavyLight) SubtreeApply(s *SegmentTree, n NI, u float64) {
+	lo, hi := h.Subtree(n)
+	s.Apply(lo, hi, u)
+}
@@ -0,0 +1,256 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleNewSegmentTree() {
+	s := graph.NewSegmentTree([]float64{3, 1, 4, 1, 5, 9, 2, 6}, graph.MaxAdd)
+	fmt.Println(s.Query(0, 4), s.Query(2, 8))
+	s.Apply(0, 4, 10)
+	s.Set(5, 0)
+	fmt.Println(s.Query(0, 4), s.Query(4, 8), s.Get(2))
+	// Output:
+	// 4 9
+	// 14 6 14
+}
+
+func ExampleFromList_HeavyLight() {
+	//       0
+	//      / \
+	//     1   2
+	//    /|\   \
+	//   3 4 5   6
+	//       |
+	//       7
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+		5: {From: 1},
+		6: {From: 2},
+		7: {From: 5},
+	}}
+	h := f.HeavyLight()
+	order := make([]graph.NI, len(f.Paths))
+	for pos := range order {
+		order[pos] = h.Node(pos)
+	}
+	fmt.Println("position order:", order)
+	fmt.Println("path 7 to 6:", h.PathRanges(7, 6))
+	fmt.Println(h.Subtree(1))
+	// Output:
+	// position order: [0 1 5 7 4 3 2 6]
+	// path 7 to 6: [{0 4 true} {6 8 false}]
+	// 1 6
+}
+
+func ExampleHeavyLight_PathQuery() {
+	//       0
+	//      / \
+	//     1   2
+	//    /|\   \
+	//   3 4 5   6
+	//       |
+	//       7
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+		5: {From: 1},
+		6: {From: 2},
+		7: {From: 5},
+	}}
+	h := f.HeavyLight()
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	s := h.SegmentTree(values, graph.SumAdd)
+	fmt.Println("sum on path 7 to 6:", h.PathQuery(s, 7, 6))
+	fmt.Println("sum in subtree 1:", h.SubtreeQuery(s, 1))
+	h.SubtreeApply(s, 5, 10)
+	fmt.Println("sum on path 7 to 6:", h.PathQuery(s, 7, 6))
+	h.PathApply(s, 3, 4, -1)
+	fmt.Println("sum in subtree 1:", h.SubtreeQuery(s, 1))
+	// Output:
+	// sum on path 7 to 6: 27
+	// sum in subtree 1: 25
+	// sum on path 7 to 6: 47
+	// sum in subtree 1: 42
+}
+
+func TestHeavyLight(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 20; i++ {
+		f := randomForest(1+r.Intn(200), .05, r)
+		p := f.Paths
+		h := f.HeavyLight()
+		values := make([]float64, len(p))
+		for n := range values {
+			values[n] = float64(r.Intn(100))
+		}
+		sum := h.SegmentTree(values, graph.SumAdd)
+		max := h.SegmentTree(values, graph.MaxAdd)
+		// naive path from a to b
+		path := func(a, b graph.NI) (nodes []graph.NI) {
+			c := f.CommonStart(a, b)
+			if c < 0 {
+				return nil
+			}
+			for ; a != c; a = p[a].From {
+				nodes = append(nodes, a)
+			}
+			nodes = append(nodes, c)
+			for ; b != c; b = p[b].From {
+				nodes = append(nodes, b)
+			}
+			return
+		}
+		for j := 0; j < 200; j++ {
+			a := graph.NI(r.Intn(len(p)))
+			b := graph.NI(r.Intn(len(p)))
+			if got, want := h.LCA(a, b), f.CommonStart(a, b); got != want {
+				t.Fatal("LCA", a, b, got, want)
+			}
+			nodes := path(a, b)
+			n := 0
+			for _, pr := range h.PathRanges(a, b) {
+				n += pr.Hi - pr.Lo
+			}
+			if n != len(nodes) {
+				t.Fatal("PathRanges", a, b, n, len(nodes))
+			}
+			if len(nodes) == 0 {
+				continue
+			}
+			if r.Intn(2) == 0 {
+				u := float64(r.Intn(10))
+				h.PathApply(sum, a, b, u)
+				h.PathApply(max, a, b, u)
+				for _, n := range nodes {
+					values[n] += u
+				}
+			} else {
+				u := float64(r.Intn(10))
+				h.SubtreeApply(sum, a, u)
+				h.SubtreeApply(max, a, u)
+				lo, hi := h.Subtree(a)
+				for pos := lo; pos < hi; pos++ {
+					values[h.Node(pos)] += u
+				}
+			}
+			ws, wm := 0., values[nodes[0]]
+			for _, n := range nodes {
+				ws += values[n]
+				if values[n] > wm {
+					wm = values[n]
+				}
+			}
+			if got := h.PathQuery(sum, a, b); got != ws {
+				t.Fatal("PathQuery sum", a, b, got, ws)
+			}
+			if got := h.PathQuery(max, a, b); got != wm {
+				t.Fatal("PathQuery max", a, b, got, wm)
+			}
+		}
+		// subtree ranges contain exactly the descendants
+		for n := range p {
+			lo, hi := h.Subtree(graph.NI(n))
+			for pos := lo; pos < hi; pos++ {
+				m := h.Node(pos)
+				if f.CommonStart(graph.NI(n), m) != graph.NI(n) {
+					t.Fatal("Subtree", n, m)
+				}
+			}
+			if h.Pos(h.Node(h.Pos(graph.NI(n)))) != h.Pos(graph.NI(n)) {
+				t.Fatal("Pos", n)
+			}
+		}
+	}
+}
+
+// firstValue aggregates by taking the first value that is not NaN.  It is
+// associative but not commutative.
+var firstValue = graph.Aggregate{
+	Combine: func(a, b float64) float64 {
+		if math.IsNaN(a) {
+			return b
+		}
+		return a
+	},
+	Identity: math.NaN(),
+}
+
+func ExampleHeavyLight_PathQuery_nonCommutative() {
+	// path 0-1-2-3
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 1},
+		3: {From: 2},
+	}}
+	h := f.HeavyLight()
+	s := h.SegmentTree([]float64{0, 1, 2, 3}, firstValue)
+	fmt.Println(h.PathQuery(s, 0, 3), h.PathQuery(s, 3, 0), h.PathQuery(s, 2, 1))
+	// Output:
+	// 0 3 2
+}
+
+func TestHeavyLightPathOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 20; i++ {
+		f := randomForest(1+r.Intn(200), .05, r)
+		p := f.Paths
+		h := f.HeavyLight()
+		// most values NaN so the first value found depends on path order
+		values := make([]float64, len(p))
+		for n := range values {
+			values[n] = math.NaN()
+			if r.Intn(4) == 0 {
+				values[n] = float64(n)
+			}
+		}
+		s := h.SegmentTree(values, firstValue)
+		for j := 0; j < 200; j++ {
+			a := graph.NI(r.Intn(len(p)))
+			b := graph.NI(r.Intn(len(p)))
+			c := f.CommonStart(a, b)
+			if c < 0 {
+				continue
+			}
+			// nodes in path order from a to b
+			var nodes, down []graph.NI
+			for n := a; n != c; n = p[n].From {
+				nodes = append(nodes, n)
+			}
+			nodes = append(nodes, c)
+			for n := b; n != c; n = p[n].From {
+				down = append(down, n)
+			}
+			for k := len(down) - 1; k >= 0; k-- {
+				nodes = append(nodes, down[k])
+			}
+			want := math.NaN()
+			for _, n := range nodes {
+				if !math.IsNaN(values[n]) {
+					want = values[n]
+					break
+				}
+			}
+			got := h.PathQuery(s, a, b)
+			if got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+				t.Fatal("PathQuery", a, b, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "math/rand"
+
+// labelprop.go has label propagation community detection.
+//
+// Each node starts with a unique label.  Nodes then repeatedly adopt the
+// label most frequent among their neighbors until each node has a label
+// that is among the most frequent of its neighbors.  Each pass over the
+// nodes takes time linear in the size of the graph and few passes are
+// typically needed.
+//
+// Ties are broken randomly, except that a node keeps its current label if
+// it is among the most frequent.  This rule guarantees termination of the
+// semi-synchronous variant and in practice of the asynchronous variant.
+
+// lpRun runs label propagation over nodes in the order given by func
+// order, called before each pass.
+func (g Undirected) lpRun(ri func(int) int, order func([]NI)) (ci []int, nc int) {
+	a := g.AdjacencyList
+	label := make([]NI, len(a))
+	ord := make([]NI, len(a))
+	for n := range label {
+		label[n] = NI(n)
+		ord[n] = NI(n)
+	}
+	cnt := make([]int, len(a))
+	var seen, best []NI
+	for changed := true; changed; {
+		changed = false
+		order(ord)
+		for _, n := range ord {
+			seen = seen[:0]
+			for _, to := range a[n] {
+				if to == n {
+					continue
+				}
+				l := label[to]
+				if cnt[l] == 0 {
+					seen = append(seen, l)
+				}
+				cnt[l]++
+			}
+			if len(seen) == 0 {
+				continue
+			}
+			max := 0
+			for _, l := range seen {
+				if cnt[l] > max {
+					max = cnt[l]
+				}
+			}
+			if cnt[label[n]] < max {
+				best = best[:0]
+				for _, l := range seen {
+					if cnt[l] == max {
+						best = append(best, l)
+					}
+				}
+				label[n] = best[ri(len(best))]
+				changed = true
+			}
+			for _, l := range seen {
+				cnt[l] = 0
+			}
+		}
+	}
+	// renumber 1-based, in order of first occurrence
+	ci = make([]int, len(a))
+	num := cnt // reuse, all zero
+	for n, l := range label {
+		if num[l] == 0 {
+			nc++
+			num[l] = nc
+		}
+		ci[n] = num[l]
+	}
+	return
+}
+
+// LabelPropagation finds communities in an undirected graph by
+// asynchronous label propagation, the algorithm of Raghavan, Albert, and
+// Kumara.
+//
+// In each pass, nodes are visited in a new random order and each node
+// immediately adopts the most frequent label among its neighbors.  Loops
+// are ignored and parallel edges count multiply.  Results depend on random
+// choices.  For reproducible results, pass a seeded *rand.Rand.  If rr is
+// nil, the rand package default shared source is used.
+//
+// Communities are returned in the form of ConnectedComponentInts, with
+// community numbers 1 through nc in ci.  Each community is contained in a
+// connected component.
+//
+// See also SemiSyncLabelPropagation.
+func (g Undirected) LabelPropagation(rr *rand.Rand) (ci []int, nc int) {
+	ri := rand.Intn
+	if rr != nil {
+		ri = rr.Intn
+	}
+	return g.lpRun(ri, func(ord []NI) {
+		for i := len(ord) - 1; i > 0; i-- {
+			j := ri(i + 1)
+			ord[i], ord[j] = ord[j], ord[i]
+		}
+	})
+}
+
+// SemiSyncLabelPropagation finds communities in an undirected graph by
+// semi-synchronous label propagation, the algorithm of Cordasco and
+// Gargano.
+//
+// Nodes are first colored so that adjacent nodes have different colors.
+// In each pass, nodes of each color class are updated synchronously, that
+// is, from labels computed before updating the class.  This avoids the
+// oscillation of fully synchronous label propagation while giving results
+// less dependent on update order than asynchronous label propagation.
+// Random choices are needed only to break ties.  For reproducible results,
+// pass a seeded *rand.Rand.  If rr is nil, the rand package default shared
+// source is used.
+//
+// Communities are returned in the form of ConnectedComponentInts, with
+// community numbers 1 through nc in ci.  Each community is contained in a
+// connected component.
+//
+// See also LabelPropagation.
+func (g Undirected) SemiSyncLabelPropagation(rr *rand.Rand) (ci []int, nc int) {
+	ri := rand.Intn
+	if rr != nil {
+		ri = rr.Intn
+	}
+	// greedy coloring, then nodes ordered by color.  nodes of a color class
+	// are not adjacent so updating them in sequence is the same as updating
+	// them synchronously.
+	a := g.AdjacencyList
+	color := make([]int, len(a))
+	used := make([]int, len(a)+1) // node+1 that last used each color
+	var classes [][]NI
+	for n, to := range a {
+		for _, to := range to {
+			if to < NI(n) {
+				used[color[to]] = n + 1
+			}
+		}
+		c := 0
+		for used[c] == n+1 {
+			c++
+		}
+		color[n] = c
+		if c == len(classes) {
+			classes = append(classes, nil)
+		}
+		classes[c] = append(classes[c], NI(n))
+	}
+	var byColor []NI
+	for _, cl := range classes {
+		byColor = append(byColor, cl...)
+	}
+	return g.lpRun(ri, func(ord []NI) { copy(ord, byColor) })
+}
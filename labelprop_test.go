@@ -0,0 +1,129 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_LabelPropagation() {
+	// two 4-cliques joined by an edge, and an isolated node
+	var g graph.Undirected
+	for _, c := range [][]graph.NI{{0, 1, 2, 3}, {4, 5, 6, 7}} {
+		for i, n1 := range c {
+			for _, n2 := range c[i+1:] {
+				g.AddEdge(n1, n2)
+			}
+		}
+	}
+	g.AddEdge(3, 4)
+	g.AddEdge(8, 8)
+	fmt.Println(g.LabelPropagation(rand.New(rand.NewSource(1))))
+	// Random output:
+	// [1 1 1 1 2 2 2 2 3] 3
+}
+
+func ExampleUndirected_SemiSyncLabelPropagation() {
+	// two 4-cliques joined by an edge, and an isolated node
+	var g graph.Undirected
+	for _, c := range [][]graph.NI{{0, 1, 2, 3}, {4, 5, 6, 7}} {
+		for i, n1 := range c {
+			for _, n2 := range c[i+1:] {
+				g.AddEdge(n1, n2)
+			}
+		}
+	}
+	g.AddEdge(3, 4)
+	g.AddEdge(8, 8)
+	fmt.Println(g.SemiSyncLabelPropagation(rand.New(rand.NewSource(1))))
+	// Random output:
+	// [1 1 1 1 2 2 2 2 3] 3
+}
+
+func TestLabelPropagationCliques(t *testing.T) {
+	// two 4-cliques joined by an edge, and an isolated node.  at
+	// convergence each clique is a single community, possibly the same one.
+	var g graph.Undirected
+	for _, c := range [][]graph.NI{{0, 1, 2, 3}, {4, 5, 6, 7}} {
+		for i, n1 := range c {
+			for _, n2 := range c[i+1:] {
+				g.AddEdge(n1, n2)
+			}
+		}
+	}
+	g.AddEdge(3, 4)
+	g.AddEdge(8, 8)
+	r := rand.New(rand.NewSource(45))
+	for i := 0; i < 50; i++ {
+		for _, lp := range []func(*rand.Rand) ([]int, int){
+			g.LabelPropagation, g.SemiSyncLabelPropagation,
+		} {
+			ci, nc := lp(r)
+			for _, c := range [][]int{ci[:4], ci[4:8]} {
+				for _, x := range c {
+					if x != c[0] {
+						t.Fatal("clique split", ci)
+					}
+				}
+			}
+			want := 3
+			if ci[4] == ci[0] {
+				want = 2
+			}
+			if ci[8] == ci[0] || ci[8] == ci[4] || nc != want {
+				t.Fatal(ci, nc)
+			}
+		}
+	}
+}
+
+func TestLabelPropagation(t *testing.T) {
+	r := rand.New(rand.NewSource(45))
+	for i := 0; i < 50; i++ {
+		g := graph.GnmUndirected(1+r.Intn(40), r.Intn(80), r)
+		cc, _ := g.ConnectedComponentInts()
+		for _, lp := range []func(*rand.Rand) ([]int, int){
+			g.LabelPropagation, g.SemiSyncLabelPropagation,
+		} {
+			ci, nc := lp(r)
+			// communities numbered 1..nc in order of first occurrence
+			next := 1
+			comp := map[int]int{}
+			for n, c := range ci {
+				if c == next {
+					next++
+				} else if c > next || c < 1 {
+					t.Fatal("numbering", ci)
+				}
+				if x, ok := comp[c]; ok && x != cc[n] {
+					t.Fatal("community spans components", g, ci)
+				}
+				comp[c] = cc[n]
+			}
+			if nc != next-1 {
+				t.Fatal("nc", nc, ci)
+			}
+			// each label is among the most frequent of neighbors
+			for n, to := range g.AdjacencyList {
+				cnt := map[int]int{}
+				max := 0
+				for _, to := range to {
+					if to != graph.NI(n) {
+						cnt[ci[to]]++
+						if cnt[ci[to]] > max {
+							max = cnt[ci[to]]
+						}
+					}
+				}
+				if max > 0 && cnt[ci[n]] != max {
+					t.Fatal("not converged", g, ci, n)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// lca.go has two indexes supporting lowest common ancestor and related
+// queries on a FromList, BinaryLifting and EulerTour.
+//
+// The two index types have the same query methods.  BinaryLifting is
+// simpler and answers all queries in O(log n) time.  EulerTour uses more
+// memory but answers LCA, Depth, and Distance in O(1) time.
+
+// BinaryLifting is an index for ancestor queries on a FromList.
+//
+// For each node it stores ancestors 1, 2, 4, 8... levels up.  Construction
+// takes O(n log n) time and memory.  Queries take O(log n) time.
+//
+// Construct with FromList.BinaryLifting.
+type BinaryLifting struct {
+	depth []int
+	up    [][]NI // up[j][n] is the 2^j-th ancestor of n, or -1
+}
+
+// EulerTour is an index for ancestor queries on a FromList.
+//
+// It stores an Euler tour of the tree or forest with a sparse table for
+// range minimum queries on node depth.  Construction takes O(n log n) time
+// and memory.  LCA, Depth, and Distance queries take O(1) time.
+// KthAncestor takes O(log n) time.
+//
+// Construct with FromList.EulerTour.
+type EulerTour struct {
+	depth   []int
+	root    []NI   // root of the tree containing each node
+	first   []int  // index in tour of first occurrence of each node
+	sparse  [][]NI // sparse[j][i] is the min depth node of tour[i:i+2^j]
+	byDepth [][]NI // nodes at each depth, in tour order
+}
+
+// depths computes the depth of each node of a FromList.
+//
+// Roots have depth 0.  Only the From members of p are used.
+// p must be acyclic.
+func depths(p []PathEnd) []int {
+	d := make([]int, len(p))
+	for i := range d {
+		d[i] = -1
+	}
+	var stack []NI
+	for n := range p {
+		// walk up to a node with known depth or to a root
+		m := NI(n)
+		for d[m] < 0 {
+			fr := p[m].From
+			if fr < 0 {
+				d[m] = 0
+				break
+			}
+			stack = append(stack, m)
+			m = fr
+		}
+		// then fill in depths on the way back down
+		for dm := d[m]; len(stack) > 0; {
+			last := len(stack) - 1
+			dm++
+			d[stack[last]] = dm
+			stack = stack[:last]
+		}
+	}
+	return d
+}
+
+// BinaryLifting constructs a BinaryLifting ancestor index of f.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+// It can represent a forest.
+func (f FromList) BinaryLifting() *BinaryLifting {
+	p := f.Paths
+	x := &BinaryLifting{depth: depths(p)}
+	maxDepth := 0
+	for _, d := range x.depth {
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	up0 := make([]NI, len(p))
+	for n, e := range p {
+		up0[n] = e.From
+	}
+	x.up = [][]NI{up0}
+	for j := 1; 1<<uint(j) <= maxDepth; j++ {
+		prev := x.up[j-1]
+		upj := make([]NI, len(p))
+		for n, a := range prev {
+			if a < 0 {
+				upj[n] = -1
+			} else {
+				upj[n] = prev[a]
+			}
+		}
+		x.up = append(x.up, upj)
+	}
+	return x
+}
+
+// Depth returns the depth of node n, the number of arcs on the path from
+// the root of its tree.  The depth of a root is 0.
+func (x *BinaryLifting) Depth(n NI) int {
+	return x.depth[n]
+}
+
+// KthAncestor returns the ancestor of n k levels up.
+//
+// The 0th ancestor of n is n itself.  KthAncestor returns -1 if k is
+// greater than the depth of n.
+func (x *BinaryLifting) KthAncestor(n NI, k int) NI {
+	if k > x.depth[n] {
+		return -1
+	}
+	for j := 0; k > 0; j++ {
+		if k&1 == 1 {
+			n = x.up[j][n]
+		}
+		k >>= 1
+	}
+	return n
+}
+
+// LCA returns the lowest common ancestor of nodes a and b.
+//
+// The lowest common ancestor is the deepest node that is an ancestor of
+// both a and b, where a node is considered an ancestor of itself.
+// LCA returns -1 if a and b are in different trees of a forest.
+//
+// The result is the same as FromList.CommonStart.
+func (x *BinaryLifting) LCA(a, b NI) NI {
+	d := x.depth
+	if d[a] < d[b] {
+		a, b = b, a
+	}
+	a = x.KthAncestor(a, d[a]-d[b])
+	if a == b {
+		return a
+	}
+	for j := len(x.up) - 1; j >= 0; j-- {
+		if uj := x.up[j]; uj[a] != uj[b] {
+			a = uj[a]
+			b = uj[b]
+		}
+	}
+	return x.up[0][a] // -1 if a and b were roots of different trees
+}
+
+// Distance returns the number of arcs on the tree path between a and b.
+//
+// Distance returns -1 if a and b are in different trees of a forest.
+func (x *BinaryLifting) Distance(a, b NI) int {
+	c := x.LCA(a, b)
+	if c < 0 {
+		return -1
+	}
+	return x.depth[a] + x.depth[b] - 2*x.depth[c]
+}
+
+// EulerTour constructs an EulerTour ancestor index of f.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+// It can represent a forest.
+func (f FromList) EulerTour() *EulerTour {
+	p := f.Paths
+	ch, _ := f.Transpose(nil)
+	x := &EulerTour{
+		depth: make([]int, len(p)),
+		root:  make([]NI, len(p)),
+		first: make([]int, len(p)),
+	}
+	tour := make([]NI, 0, 2*len(p))
+	type frame struct {
+		n  NI
+		cx int // index of next child to visit
+	}
+	var stack []frame
+	for r, e := range p {
+		if e.From >= 0 {
+			continue
+		}
+		x.root[r] = NI(r)
+		x.first[r] = len(tour)
+		tour = append(tour, NI(r))
+		stack = append(stack[:0], frame{NI(r), 0})
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			to := ch.AdjacencyList[top.n]
+			if top.cx == len(to) {
+				stack = stack[:len(stack)-1]
+				if len(stack) > 0 {
+					tour = append(tour, stack[len(stack)-1].n)
+				}
+				continue
+			}
+			c := to[top.cx]
+			top.cx++
+			x.depth[c] = x.depth[top.n] + 1
+			x.root[c] = NI(r)
+			x.first[c] = len(tour)
+			tour = append(tour, c)
+			stack = append(stack, frame{c, 0})
+		}
+	}
+	// sparse table
+	x.sparse = [][]NI{tour}
+	for j := 1; 1<<uint(j) <= len(tour); j++ {
+		prev := x.sparse[j-1]
+		h := 1 << uint(j-1)
+		sj := make([]NI, len(tour)-2*h+1)
+		for i := range sj {
+			a, b := prev[i], prev[i+h]
+			if x.depth[b] < x.depth[a] {
+				a = b
+			}
+			sj[i] = a
+		}
+		x.sparse = append(x.sparse, sj)
+	}
+	// nodes by depth, for KthAncestor
+	for _, n := range tour {
+		d := x.depth[n]
+		if d == len(x.byDepth) {
+			x.byDepth = append(x.byDepth, nil)
+		}
+		if l := x.byDepth[d]; len(l) == 0 || x.first[l[len(l)-1]] < x.first[n] {
+			x.byDepth[d] = append(l, n)
+		}
+	}
+	return x
+}
+
+// Depth returns the depth of node n, the number of arcs on the path from
+// the root of its tree.  The depth of a root is 0.
+func (x *EulerTour) Depth(n NI) int {
+	return x.depth[n]
+}
+
+// KthAncestor returns the ancestor of n k levels up.
+//
+// The 0th ancestor of n is n itself.  KthAncestor returns -1 if k is
+// greater than the depth of n.
+func (x *EulerTour) KthAncestor(n NI, k int) NI {
+	d := x.depth[n] - k
+	if d < 0 {
+		return -1
+	}
+	// the ancestor is the last node at depth d starting at or before n
+	l := x.byDepth[d]
+	fn := x.first[n]
+	i := sort.Search(len(l), func(i int) bool { return x.first[l[i]] > fn })
+	return l[i-1]
+}
+
+// LCA returns the lowest common ancestor of nodes a and b.
+//
+// The lowest common ancestor is the deepest node that is an ancestor of
+// both a and b, where a node is considered an ancestor of itself.
+// LCA returns -1 if a and b are in different trees of a forest.
+//
+// The result is the same as FromList.CommonStart.
+func (x *EulerTour) LCA(a, b NI) NI {
+	if x.root[a] != x.root[b] {
+		return -1
+	}
+	i, j := x.first[a], x.first[b]
+	if i > j {
+		i, j = j, i
+	}
+	k := bits.Len(uint(j-i+1)) - 1
+	sk := x.sparse[k]
+	c1, c2 := sk[i], sk[j-(1<<uint(k))+1]
+	if x.depth[c2] < x.depth[c1] {
+		return c2
+	}
+	return c1
+}
+
+// Distance returns the number of arcs on the tree path between a and b.
+//
+// Distance returns -1 if a and b are in different trees of a forest.
+func (x *EulerTour) Distance(a, b NI) int {
+	c := x.LCA(a, b)
+	if c < 0 {
+		return -1
+	}
+	return x.depth[a] + x.depth[b] - 2*x.depth[c]
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleFromList_BinaryLifting() {
+	//   4   5
+	//  /   /
+	// 6   1
+	//    / \
+	//   0   2
+	//  /
+	// 3
+	f := graph.FromList{Paths: []graph.PathEnd{
+		4: {From: -1},
+		6: {From: 4},
+		5: {From: -1},
+		1: {From: 5},
+		0: {From: 1},
+		2: {From: 1},
+		3: {From: 0},
+	}}
+	x := f.BinaryLifting()
+	fmt.Println("LCA:", x.LCA(2, 3), x.LCA(6, 3))
+	fmt.Println("Depth:", x.Depth(3))
+	fmt.Println("KthAncestor:", x.KthAncestor(3, 2), x.KthAncestor(3, 4))
+	fmt.Println("Distance:", x.Distance(2, 3), x.Distance(6, 3))
+	// Output:
+	// LCA: 1 -1
+	// Depth: 3
+	// KthAncestor: 1 -1
+	// Distance: 3 -1
+}
+
+func ExampleFromList_EulerTour() {
+	//   4   5
+	//  /   /
+	// 6   1
+	//    / \
+	//   0   2
+	//  /
+	// 3
+	f := graph.FromList{Paths: []graph.PathEnd{
+		4: {From: -1},
+		6: {From: 4},
+		5: {From: -1},
+		1: {From: 5},
+		0: {From: 1},
+		2: {From: 1},
+		3: {From: 0},
+	}}
+	x := f.EulerTour()
+	fmt.Println("LCA:", x.LCA(2, 3), x.LCA(6, 3))
+	fmt.Println("Depth:", x.Depth(3))
+	fmt.Println("KthAncestor:", x.KthAncestor(3, 2), x.KthAncestor(3, 4))
+	fmt.Println("Distance:", x.Distance(2, 3), x.Distance(6, 3))
+	// Output:
+	// LCA: 1 -1
+	// Depth: 3
+	// KthAncestor: 1 -1
+	// Distance: 3 -1
+}
+
+// randomForest returns a random forest of n nodes where each node is a
+// root with probability pRoot.  Node numbers are randomly permuted.
+func randomForest(n int, pRoot float64, r *rand.Rand) graph.FromList {
+	perm := r.Perm(n)
+	f := graph.NewFromList(n)
+	for i, n := range perm {
+		if i == 0 || r.Float64() < pRoot {
+			f.Paths[n].From = -1
+		} else {
+			f.Paths[n].From = graph.NI(perm[r.Intn(i)])
+		}
+	}
+	f.RecalcLeaves()
+	f.RecalcLen()
+	return f
+}
+
+func TestLCA(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 20; i++ {
+		f := randomForest(1+r.Intn(200), .05, r)
+		bl := f.BinaryLifting()
+		et := f.EulerTour()
+		p := f.Paths
+		for j := 0; j < 200; j++ {
+			a := graph.NI(r.Intn(len(p)))
+			b := graph.NI(r.Intn(len(p)))
+			want := f.CommonStart(a, b)
+			if got := bl.LCA(a, b); got != want {
+				t.Fatal("BinaryLifting LCA", a, b, got, want)
+			}
+			if got := et.LCA(a, b); got != want {
+				t.Fatal("EulerTour LCA", a, b, got, want)
+			}
+			if bl.Depth(a) != p[a].Len-1 || et.Depth(a) != p[a].Len-1 {
+				t.Fatal("Depth", a)
+			}
+			k := r.Intn(p[a].Len + 1)
+			want = a
+			for i := 0; i < k && want >= 0; i++ {
+				want = p[want].From
+			}
+			if got := bl.KthAncestor(a, k); got != want {
+				t.Fatal("BinaryLifting KthAncestor", a, k, got, want)
+			}
+			if got := et.KthAncestor(a, k); got != want {
+				t.Fatal("EulerTour KthAncestor", a, k, got, want)
+			}
+			if bl.Distance(a, b) != et.Distance(a, b) {
+				t.Fatal("Distance", a, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// louvain.go has modularity and Louvain community detection.
+//
+// Modularity here treats a loop of weight w as contributing 2w to the
+// strength of its node, consistent with loops counting twice in the degree
+// of a node.  Internally graphs are represented as lists of weighted arcs
+// where a loop entry holds this doubled weight.
+
+// lvArc is a weighted arc.
+type lvArc struct {
+	to int
+	w  float64
+}
+
+func (g AdjacencyList) lvArcs() [][]lvArc {
+	adj := make([][]lvArc, len(g))
+	for fr, to := range g {
+		for _, to := range to {
+			w := 1.
+			if to == NI(fr) {
+				w = 2
+			}
+			adj[fr] = append(adj[fr], lvArc{int(to), w})
+		}
+	}
+	return adj
+}
+
+func (g LabeledAdjacencyList) lvArcs(wf WeightFunc) [][]lvArc {
+	adj := make([][]lvArc, len(g))
+	for fr, to := range g {
+		for _, h := range to {
+			w := wf(h.Label)
+			if h.To == NI(fr) {
+				w *= 2
+			}
+			adj[fr] = append(adj[fr], lvArc{int(h.To), w})
+		}
+	}
+	return adj
+}
+
+// modularity of a partition of adj.
+func modularity(adj [][]lvArc, partition []int) float64 {
+	in := map[int]float64{}
+	tot := map[int]float64{}
+	m2 := 0.
+	for fr, to := range adj {
+		c := partition[fr]
+		for _, a := range to {
+			tot[c] += a.w
+			m2 += a.w
+			if partition[a.to] == c {
+				in[c] += a.w
+			}
+		}
+	}
+	if m2 == 0 {
+		return 0
+	}
+	q := 0.
+	for c, t := range tot {
+		q += in[c]/m2 - (t/m2)*(t/m2)
+	}
+	return q
+}
+
+// Modularity computes the modularity of a partition of the nodes of an
+// undirected graph.
+//
+// Argument partition must have an element for each node of g.  Nodes with
+// the same value are in the same community.  Values need not be consecutive.
+//
+// Modularity is the fraction of edges within communities minus the
+// expected fraction if edges were placed at random preserving node degrees.
+// It ranges from -1/2 to 1.  The modularity of a graph with no edges is 0.
+func (g Undirected) Modularity(partition []int) float64 {
+	return modularity(g.AdjacencyList.lvArcs(), partition)
+}
+
+// Modularity computes the modularity of a partition of the nodes of an
+// undirected graph with weighted edges.
+//
+// Edge weights are given by WeightFunc w and must be non-negative.
+// Otherwise the method is the same as Undirected.Modularity.
+func (g LabeledUndirected) Modularity(w WeightFunc, partition []int) float64 {
+	return modularity(g.LabeledAdjacencyList.lvArcs(w), partition)
+}
+
+// lvMove does the local moving phase of Louvain, repeatedly moving single
+// nodes to neighboring communities while modularity increases.  It
+// returns community numbers 1 through nc for each node of adj, where each
+// community is connected.
+func lvMove(adj [][]lvArc) (comm []int, nc int) {
+	n := len(adj)
+	k := make([]float64, n)   // node strength
+	tot := make([]float64, n) // community strength
+	comm = make([]int, n)
+	m2 := 0.
+	for i, to := range adj {
+		for _, a := range to {
+			k[i] += a.w
+		}
+		tot[i] = k[i]
+		comm[i] = i
+		m2 += k[i]
+	}
+	if m2 > 0 {
+		eps := 1e-12 * m2
+		kc := make([]float64, n) // weight from current node to community
+		seen := make([]bool, n)
+		var nbc []int // neighboring communities
+		for moved := true; moved; {
+			moved = false
+			for i, to := range adj {
+				ci := comm[i]
+				nbc = nbc[:0]
+				for _, a := range to {
+					if a.to == i {
+						continue
+					}
+					c := comm[a.to]
+					if !seen[c] {
+						seen[c] = true
+						nbc = append(nbc, c)
+					}
+					kc[c] += a.w
+				}
+				// gain of moving i into community c, less a constant
+				tot[ci] -= k[i]
+				best := ci
+				bestGain := kc[ci] - tot[ci]*k[i]/m2
+				for _, c := range nbc {
+					if g := kc[c] - tot[c]*k[i]/m2; g > bestGain+eps {
+						best, bestGain = c, g
+					}
+				}
+				tot[best] += k[i]
+				if best != ci {
+					comm[i] = best
+					moved = true
+				}
+				for _, c := range nbc {
+					kc[c] = 0
+					seen[c] = false
+				}
+			}
+		}
+	}
+	// renumber as connected components within communities.  splitting a
+	// disconnected community never decreases modularity.
+	cc := make([]int, n)
+	var stack []int
+	for i := range adj {
+		if cc[i] > 0 {
+			continue
+		}
+		nc++
+		cc[i] = nc
+		stack = append(stack[:0], i)
+		for len(stack) > 0 {
+			last := len(stack) - 1
+			j := stack[last]
+			stack = stack[:last]
+			for _, a := range adj[j] {
+				if cc[a.to] == 0 && comm[a.to] == comm[j] && a.w > 0 {
+					cc[a.to] = nc
+					stack = append(stack, a.to)
+				}
+			}
+		}
+	}
+	return cc, nc
+}
+
+// lvAggregate returns the graph of communities of adj, where arcs between
+// communities accumulate weights of arcs between their members.
+func lvAggregate(adj [][]lvArc, comm []int, nc int) [][]lvArc {
+	agg := make([][]lvArc, nc)
+	w := make([]float64, nc)
+	seen := make([]bool, nc)
+	members := make([][]int, nc)
+	for i, c := range comm {
+		members[c-1] = append(members[c-1], i)
+	}
+	var nbc []int
+	for c, m := range members {
+		nbc = nbc[:0]
+		for _, i := range m {
+			for _, a := range adj[i] {
+				c2 := comm[a.to] - 1
+				if !seen[c2] {
+					seen[c2] = true
+					nbc = append(nbc, c2)
+				}
+				w[c2] += a.w
+			}
+		}
+		for _, c2 := range nbc {
+			agg[c] = append(agg[c], lvArc{c2, w[c2]})
+			w[c2] = 0
+			seen[c2] = false
+		}
+	}
+	return agg
+}
+
+func louvain(adj [][]lvArc) (community []int, nc int, q float64, levels [][]int) {
+	orig := adj
+	community = make([]int, len(adj))
+	for n := range community {
+		community[n] = n + 1
+	}
+	for {
+		comm, nc2 := lvMove(adj)
+		if nc2 == len(adj) && levels != nil {
+			break // no change
+		}
+		next := make([]int, len(community))
+		for n, c := range community {
+			next[n] = comm[c-1]
+		}
+		community, nc = next, nc2
+		levels = append(levels, community)
+		if nc2 == len(adj) {
+			break
+		}
+		adj = lvAggregate(adj, comm, nc2)
+	}
+	return community, nc, modularity(orig, community), levels
+}
+
+// Louvain partitions the nodes of an undirected graph into communities by
+// the Louvain method of Blondel et al.
+//
+// The method heuristically maximizes modularity, as computed by
+// Modularity.  In each level, nodes are repeatedly moved singly to the
+// neighboring community giving the greatest increase in modularity until
+// no move increases modularity.  Communities are then aggregated into
+// single nodes of a new graph for the next level.  Levels continue until a
+// level makes no change.
+//
+// A known weakness of the Louvain method is that it can produce
+// disconnected communities.  This implementation splits any community that
+// is not connected into its connected components at each level, which
+// never decreases modularity.  It does not implement the full refinement
+// phase of the Leiden algorithm.
+//
+// Nodes are visited in order of node number and so results are
+// deterministic.
+//
+// Returned are community numbers for each node, 1 through nc, the
+// modularity of the partition, and the partition at each level.  Each
+// partition in levels is a list of community numbers for each node of g.
+// The last element of levels is the same as community.
+func (g Undirected) Louvain() (community []int, nc int, q float64, levels [][]int) {
+	return louvain(g.AdjacencyList.lvArcs())
+}
+
+// Louvain partitions the nodes of an undirected graph with weighted edges
+// into communities by the Louvain method of Blondel et al.
+//
+// Edge weights are given by WeightFunc w and must be non-negative.
+// Otherwise the method is the same as Undirected.Louvain.
+func (g LabeledUndirected) Louvain(w WeightFunc) (community []int, nc int, q float64, levels [][]int) {
+	return louvain(g.LabeledAdjacencyList.lvArcs(w))
+}
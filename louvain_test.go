@@ -0,0 +1,109 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Louvain() {
+	// two triangles joined by an edge
+	//
+	//   0       4
+	//   |\     /|
+	//   | 2---3 |
+	//   |/     \|
+	//   1       5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 5)
+	g.AddEdge(4, 5)
+	c, nc, q, levels := g.Louvain()
+	fmt.Println(c, nc)
+	fmt.Printf("%.4f\n", q)
+	fmt.Println(len(levels))
+	// Output:
+	// [1 1 1 2 2 2] 2
+	// 0.3571
+	// 1
+}
+
+func ExampleUndirected_Modularity() {
+	// two triangles joined by an edge
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 5)
+	g.AddEdge(4, 5)
+	fmt.Printf("%.4f\n", g.Modularity([]int{0, 0, 0, 1, 1, 1}))
+	fmt.Printf("%.4f\n", g.Modularity([]int{0, 0, 0, 0, 0, 0}))
+	fmt.Printf("%.4f\n", g.Modularity([]int{0, 1, 2, 3, 4, 5}))
+	// Output:
+	// 0.3571
+	// 0.0000
+	// -0.1735
+}
+
+func TestLouvain(t *testing.T) {
+	r := rand.New(rand.NewSource(44))
+	// planted partition, 4 groups of 8 nodes, dense within groups
+	const k, s = 4, 8
+	var g graph.LabeledUndirected
+	g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, k*s)
+	for i := 0; i < k*s; i++ {
+		for j := i + 1; j < k*s; j++ {
+			p := .05
+			if i/s == j/s {
+				p = .8
+			}
+			if r.Float64() < p {
+				g.AddEdge(graph.Edge{graph.NI(i), graph.NI(j)}, graph.LI(1+r.Intn(3)))
+			}
+		}
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	c, nc, q, levels := g.Louvain(w)
+	planted := make([]int, k*s)
+	for i := range planted {
+		planted[i] = i / s
+	}
+	if nc != k || q < g.Modularity(w, planted) {
+		t.Fatal("community", c, q, g.Modularity(w, planted))
+	}
+	if math.Abs(q-g.Modularity(w, c)) > 1e-12 {
+		t.Fatal("q", q, g.Modularity(w, c))
+	}
+	for i, l := range levels {
+		seen := map[int]bool{}
+		for _, x := range l {
+			seen[x] = true
+		}
+		if i > 0 && len(seen) >= len(levels[i-1]) {
+			t.Fatal("levels", levels)
+		}
+	}
+	// unweighted, with a loop and an isolated node
+	u := graph.Undirected{g.Unlabeled()}
+	u.AddEdge(3, 3)
+	u.AdjacencyList = append(u.AdjacencyList, nil)
+	c, nc, q, _ = u.Louvain()
+	if nc != k+1 || c[k*s] != nc {
+		t.Fatal("unweighted", nc, c)
+	}
+	if math.Abs(q-u.Modularity(c)) > 1e-12 {
+		t.Fatal("q", q, u.Modularity(c))
+	}
+}
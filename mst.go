@@ -10,77 +10,6 @@ import (
 	"github.com/soniakeys/bits"
 )
 
-type dsElement struct {
-	from NI
-	rank int
-}
-
-type disjointSet struct {
-	set []dsElement
-}
-
-func newDisjointSet(n int) disjointSet {
-	set := make([]dsElement, n)
-	for i := range set {
-		set[i].from = -1
-	}
-	return disjointSet{set}
-}
-
-// return true if disjoint trees were combined.
-// false if x and y were already in the same tree.
-func (ds disjointSet) union(x, y NI) bool {
-	xr := ds.find(x)
-	yr := ds.find(y)
-	if xr == yr {
-		return false
-	}
-	switch xe, ye := &ds.set[xr], &ds.set[yr]; {
-	case xe.rank < ye.rank:
-		xe.from = yr
-	case xe.rank == ye.rank:
-		xe.rank++
-		fallthrough
-	default:
-		ye.from = xr
-	}
-	return true
-}
-
-func (ds disjointSet) find(n NI) NI {
-	// fast paths for n == root or from root.
-	// no updates need in these cases.
-	s := ds.set
-	fr := s[n].from
-	if fr < 0 { // n is root
-		return n
-	}
-	n, fr = fr, s[fr].from
-	if fr < 0 { // n is from root
-		return n
-	}
-	// otherwise updates needed.
-	// two iterative passes (rather than recursion or stack)
-	// pass 1: find root
-	r := fr
-	for {
-		f := s[r].from
-		if f < 0 {
-			break
-		}
-		r = f
-	}
-	// pass 2: update froms
-	for {
-		s[n].from = r
-		if fr == r {
-			return r
-		}
-		n = fr
-		fr = s[n].from
-	}
-}
-
 // Kruskal implements Kruskal's algorithm for constructing a minimum spanning
 // forest on an undirected graph.
 //
@@ -130,10 +59,10 @@ func (l WeightedEdgeList) Kruskal() (g LabeledUndirected, dist float64) {
 //
 // Also returned is a total distance for the returned forest.
 func (l WeightedEdgeList) KruskalSorted() (g LabeledUndirected, dist float64) {
-	ds := newDisjointSet(l.Order)
+	ds := NewUnionFind(l.Order)
 	g.LabeledAdjacencyList = make(LabeledAdjacencyList, l.Order)
 	for _, e := range l.Edges {
-		if ds.union(e.N1, e.N2) {
+		if ds.Union(e.N1, e.N2) {
 			g.AddEdge(Edge{e.N1, e.N2}, e.LI)
 			dist += l.WeightFunc(e.LI)
 		}
@@ -0,0 +1,299 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/soniakeys/bits"
+)
+
+// mstsens.go has methods analyzing minimum spanning trees: sensitivity of
+// the tree to edge weight changes, second best spanning trees, and
+// enumeration of spanning trees in order of weight.
+//
+// Methods here identify edges by label and so generally require that
+// edge labels be unique.
+
+// mstAnalysis holds a minimum spanning forest with indexes supporting
+// path queries.
+type mstAnalysis struct {
+	w       WeightFunc
+	tree    LabeledUndirected
+	dist    float64
+	f       FromList
+	labels  []LI // label of tree edge to parent in f
+	bl      *BinaryLifting
+	hl      *HeavyLight
+	nonTree []LabeledEdge // edges of g not in tree, excluding loops
+}
+
+// mstEdges returns the edges of g, excluding loops, sorted by weight.
+func (g LabeledUndirected) mstEdges(w WeightFunc) []LabeledEdge {
+	var edges []LabeledEdge
+	g.Edges(func(e LabeledEdge) {
+		if e.N1 != e.N2 {
+			edges = append(edges, e)
+		}
+	})
+	sort.SliceStable(edges, func(i, j int) bool {
+		return w(edges[i].LI) < w(edges[j].LI)
+	})
+	return edges
+}
+
+func (g LabeledUndirected) mstAnalysis(w WeightFunc) *mstAnalysis {
+	x := &mstAnalysis{w: w}
+	x.tree.LabeledAdjacencyList = make(LabeledAdjacencyList, g.Order())
+	ds := NewUnionFind(g.Order())
+	for _, e := range g.mstEdges(w) {
+		if ds.Union(e.N1, e.N2) {
+			x.tree.AddEdge(e.Edge, e.LI)
+			x.dist += w(e.LI)
+		} else {
+			x.nonTree = append(x.nonTree, e)
+		}
+	}
+	x.f, x.labels, _, _ = x.tree.FromList()
+	x.bl = x.f.BinaryLifting()
+	x.hl = x.f.HeavyLight()
+	return x
+}
+
+// pathSides returns the lowest common ancestor c of a and b and nodes
+// ca and cb, the children of c on the paths to a and b.  A child is -1
+// if a or b is c.
+func (x *mstAnalysis) pathSides(a, b NI) (c, ca, cb NI) {
+	c = x.bl.LCA(a, b)
+	ca, cb = -1, -1
+	dc := x.bl.Depth(c)
+	if a != c {
+		ca = x.bl.KthAncestor(a, x.bl.Depth(a)-dc-1)
+	}
+	if b != c {
+		cb = x.bl.KthAncestor(b, x.bl.Depth(b)-dc-1)
+	}
+	return
+}
+
+// treeEdgeValues returns a SegmentTree over x.hl holding the weight of the
+// tree edge to the parent of each node.  Roots hold root.
+func (x *mstAnalysis) treeEdgeValues(agg Aggregate, root float64) *SegmentTree {
+	v := make([]float64, len(x.f.Paths))
+	for n, e := range x.f.Paths {
+		if e.From < 0 {
+			v[n] = root
+		} else {
+			v[n] = x.w(x.labels[n])
+		}
+	}
+	return x.hl.SegmentTree(v, agg)
+}
+
+// minUpdate aggregates by minimum and updates by taking the minimum of a
+// value and the update.
+var minUpdate = Aggregate{
+	Combine:  math.Min,
+	Identity: math.Inf(1),
+	Update:   func(a, u float64, n int) float64 { return math.Min(a, u) },
+	Compose:  math.Min,
+}
+
+// MSTSensitivity computes a minimum spanning forest and the sensitivity of
+// the forest to changes in edge weights.
+//
+// The forest is returned as an undirected graph, with its total distance,
+// as with Kruskal.
+//
+// The returned tolerance map is keyed by edge label and holds for each
+// edge the amount its weight can change, holding other weights constant,
+// with the returned forest remaining a minimum spanning forest.  For edges of
+// the forest this is the amount the weight can increase.  It is +Inf for
+// bridges, edges that are in every spanning forest.  For edges not in the
+// forest, tolerance is the amount the weight can decrease.  It is +Inf for
+// loops.  A tolerance of 0 indicates an edge with a tie; another minimum
+// spanning forest exists with the edge swapped in or out.
+//
+// Edge labels must be unique.  Time is O(m log² n).
+func (g LabeledUndirected) MSTSensitivity(w WeightFunc) (tree LabeledUndirected, dist float64, tolerance map[LI]float64) {
+	x := g.mstAnalysis(w)
+	tolerance = map[LI]float64{}
+	g.Edges(func(e LabeledEdge) {
+		if e.N1 == e.N2 {
+			tolerance[e.LI] = math.Inf(1)
+		}
+	})
+	maxT := x.treeEdgeValues(MaxAdd, math.Inf(-1))
+	inf := make([]float64, len(x.f.Paths))
+	for n := range inf {
+		inf[n] = math.Inf(1)
+	}
+	minT := x.hl.SegmentTree(inf, minUpdate)
+	// For each tree edge, identified by its child node, minT accumulates
+	// the minimum weight of non-tree edges with cycles through the edge.
+	// The tree edges on the path between ends of a non-tree edge are the
+	// nodes of the path excluding the common ancestor.
+	for _, e := range x.nonTree {
+		_, ca, cb := x.pathSides(e.N1, e.N2)
+		we := w(e.LI)
+		m := math.Inf(-1)
+		if ca >= 0 {
+			m = math.Max(m, x.hl.PathQuery(maxT, e.N1, ca))
+			x.hl.PathApply(minT, e.N1, ca, we)
+		}
+		if cb >= 0 {
+			m = math.Max(m, x.hl.PathQuery(maxT, e.N2, cb))
+			x.hl.PathApply(minT, e.N2, cb, we)
+		}
+		tolerance[e.LI] = we - m
+	}
+	for n, e := range x.f.Paths {
+		if e.From >= 0 {
+			l := x.labels[n]
+			tolerance[l] = minT.Get(x.hl.Pos(NI(n))) - w(l)
+		}
+	}
+	return x.tree, x.dist, tolerance
+}
+
+// SecondBestMST finds a second best minimum spanning forest.
+//
+// A second best minimum spanning forest is a spanning forest of minimum
+// weight that differs from a minimum spanning forest found by Kruskal.
+// It differs by a single edge swap.  If g has multiple minimum spanning
+// forests, the result will be one of them, with the same weight as the
+// minimum.
+//
+// The forest is returned as an undirected graph with its total distance,
+// and also returned are the labels of the edges swapped.  Edge labels must
+// be unique.  If g has only one spanning forest, ok is returned false.
+func (g LabeledUndirected) SecondBestMST(w WeightFunc) (tree LabeledUndirected, dist float64, removed, added LI, ok bool) {
+	x := g.mstAnalysis(w)
+	if len(x.nonTree) == 0 {
+		return
+	}
+	maxT := x.treeEdgeValues(MaxAdd, math.Inf(-1))
+	best := math.Inf(1)
+	var bestEdge LabeledEdge
+	for _, e := range x.nonTree {
+		_, ca, cb := x.pathSides(e.N1, e.N2)
+		m := math.Inf(-1)
+		if ca >= 0 {
+			m = math.Max(m, x.hl.PathQuery(maxT, e.N1, ca))
+		}
+		if cb >= 0 {
+			m = math.Max(m, x.hl.PathQuery(maxT, e.N2, cb))
+		}
+		if d := w(e.LI) - m; d < best {
+			best, bestEdge = d, e
+		}
+	}
+	// find the max weight tree edge on the path by walking it
+	c := x.bl.LCA(bestEdge.N1, bestEdge.N2)
+	var rm NI = -1
+	for _, n := range []NI{bestEdge.N1, bestEdge.N2} {
+		for ; n != c; n = x.f.Paths[n].From {
+			if rm < 0 || w(x.labels[n]) > w(x.labels[rm]) {
+				rm = n
+			}
+		}
+	}
+	tree = x.tree
+	removed = x.labels[rm]
+	added = bestEdge.LI
+	tree.RemoveEdgeLabel(rm, x.f.Paths[rm].From, removed)
+	tree.AddEdge(bestEdge.Edge, added)
+	return tree, x.dist + best, removed, added, true
+}
+
+// stNode is a subproblem for SpanningTreesByWeight.
+type stNode struct {
+	dist float64
+	tree []int     // edge indexes of a minimum forest of the subproblem
+	in   []int     // edge indexes required
+	out  bits.Bits // edge indexes excluded
+}
+
+type stHeap []*stNode
+
+func (h stHeap) Len() int           { return len(h) }
+func (h stHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h stHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *stHeap) Push(x interface{}) {
+	*h = append(*h, x.(*stNode))
+}
+func (h *stHeap) Pop() interface{} {
+	r := *h
+	last := len(r) - 1
+	*h = r[:last]
+	return r[last]
+}
+
+// SpanningTreesByWeight enumerates spanning forests of g in order of
+// increasing weight.
+//
+// Spanning forests are spanning trees of each connected component.  Each
+// is passed to emit as an undirected graph with its total distance.
+// Enumeration terminates when all spanning forests have been emitted or
+// when emit returns false.  Loops are ignored.  Parallel edges are allowed
+// and edges are distinguished by their position in g even if labels are
+// not unique.  The first forest emitted is a minimum spanning forest and
+// the first k emitted are k best spanning forests.
+//
+// The algorithm is Lawler's partitioning method applied to Kruskal's
+// algorithm.  Time is O(k m n) for k forests emitted.
+func (g LabeledUndirected) SpanningTreesByWeight(w WeightFunc, emit func(tree LabeledUndirected, dist float64) bool) {
+	edges := g.mstEdges(w)
+	order := g.Order()
+	// kruskal finds a minimum spanning forest with edges in required and
+	// without edges in excluded.
+	full := -1 // size of a spanning forest
+	kruskal := func(in []int, out bits.Bits) (tree []int, dist float64, ok bool) {
+		ds := NewUnionFind(order)
+		for _, x := range in {
+			ds.Union(edges[x].N1, edges[x].N2)
+			dist += w(edges[x].LI)
+		}
+		tree = append(tree, in...)
+		for x, e := range edges {
+			if out.Bit(x) == 0 && ds.Union(e.N1, e.N2) {
+				tree = append(tree, x)
+				dist += w(e.LI)
+			}
+		}
+		if full < 0 {
+			full = len(tree)
+		}
+		return tree, dist, len(tree) == full
+	}
+	t, d, _ := kruskal(nil, bits.New(len(edges)))
+	h := stHeap{{dist: d, tree: t, out: bits.New(len(edges))}}
+	for len(h) > 0 {
+		s := heap.Pop(&h).(*stNode)
+		var tr LabeledUndirected
+		tr.LabeledAdjacencyList = make(LabeledAdjacencyList, order)
+		for _, x := range s.tree {
+			tr.AddEdge(edges[x].Edge, edges[x].LI)
+		}
+		if !emit(tr, s.dist) {
+			return
+		}
+		// partition the remaining forests of s.  s.tree starts with the
+		// required edges in.  child i requires the first i free edges and
+		// excludes free edge i.
+		in := append([]int{}, s.in...)
+		for _, x := range s.tree[len(s.in):] {
+			out := bits.New(len(edges))
+			out.Set(s.out)
+			out.SetBit(x, 1)
+			if t, d, ok := kruskal(in, out); ok {
+				heap.Push(&h, &stNode{dist: d, tree: t,
+					in: append([]int{}, in...), out: out})
+			}
+			in = append(in, x)
+		}
+	}
+}
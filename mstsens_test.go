@@ -0,0 +1,176 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+// mstExample returns the graph used in Kruskal examples.
+func mstExample() graph.LabeledUndirected {
+	//       (10)
+	//     0------4----\
+	//     |     /|     \(70)
+	// (30)| (40) |(60)  \
+	//     |/     |      |
+	//     1------2------3
+	//       (50)   (20)
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 30)
+	g.AddEdge(graph.Edge{0, 4}, 10)
+	g.AddEdge(graph.Edge{1, 2}, 50)
+	g.AddEdge(graph.Edge{1, 4}, 40)
+	g.AddEdge(graph.Edge{2, 3}, 20)
+	g.AddEdge(graph.Edge{2, 4}, 60)
+	g.AddEdge(graph.Edge{3, 4}, 70)
+	return g
+}
+
+func ExampleLabeledUndirected_MSTSensitivity() {
+	g := mstExample()
+	w := func(l graph.LI) float64 { return float64(l) }
+	_, dist, tol := g.MSTSensitivity(w)
+	fmt.Println("total distance:", dist)
+	for _, l := range []graph.LI{10, 20, 30, 40, 50, 60, 70} {
+		fmt.Println(l, tol[l])
+	}
+	// Output:
+	// total distance: 110
+	// 10 30
+	// 20 50
+	// 30 10
+	// 40 10
+	// 50 10
+	// 60 10
+	// 70 20
+}
+
+func ExampleLabeledUndirected_SecondBestMST() {
+	g := mstExample()
+	w := func(l graph.LI) float64 { return float64(l) }
+	_, dist, removed, added, ok := g.SecondBestMST(w)
+	fmt.Println(ok, dist, removed, added)
+	// Output:
+	// true 120 30 40
+}
+
+func ExampleLabeledUndirected_SpanningTreesByWeight() {
+	g := mstExample()
+	w := func(l graph.LI) float64 { return float64(l) }
+	k := 0
+	g.SpanningTreesByWeight(w, func(t graph.LabeledUndirected, dist float64) bool {
+		var labels []graph.LI
+		t.Edges(func(e graph.LabeledEdge) { labels = append(labels, e.LI) })
+		sort.Slice(labels, func(i, j int) bool { return labels[i] < labels[j] })
+		fmt.Println(dist, labels)
+		k++
+		return k < 4
+	})
+	// Output:
+	// 110 [10 20 30 50]
+	// 120 [10 20 40 50]
+	// 120 [10 20 30 60]
+	// 130 [10 20 40 60]
+}
+
+func TestMSTSensitivity(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(7)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		m := r.Intn(2 * n)
+		wt := make([]float64, m)
+		for l := range wt {
+			wt[l] = float64(r.Intn(10))
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(l))
+		}
+		w := func(l graph.LI) float64 { return wt[l] }
+		// brute force all spanning forests
+		var all []float64
+		g.SpanningTreesByWeight(w, func(_ graph.LabeledUndirected, d float64) bool {
+			all = append(all, d)
+			return true
+		})
+		if !sort.Float64sAreSorted(all) {
+			t.Fatal("not in order")
+		}
+		if want := countSpanningForests(g); len(all) != want {
+			t.Fatal("count", len(all), want)
+		}
+		tree, dist, tol := g.MSTSensitivity(w)
+		if dist != all[0] {
+			t.Fatal("MSTSensitivity dist", dist, all[0])
+		}
+		sb, sbDist, _, _, ok := g.SecondBestMST(w)
+		if ok != (len(all) > 1) || ok && sbDist != all[1] {
+			t.Fatal("SecondBestMST", ok, sbDist, all)
+		}
+		if ok {
+			d := 0.
+			sb.Edges(func(e graph.LabeledEdge) { d += w(e.LI) })
+			if d != sbDist {
+				t.Fatal("SecondBestMST dist", d, sbDist)
+			}
+		}
+		// check tolerances by changing each weight and recomputing
+		g.Edges(func(e graph.LabeledEdge) {
+			inTree, _, _ := tree.HasEdgeLabel(e.N1, e.N2, e.LI)
+			tl := tol[e.LI]
+			if math.IsInf(tl, 1) {
+				return
+			}
+			orig := wt[e.LI]
+			// at the tolerance the tree is still minimum but just beyond
+			// it is not.
+			for _, delta := range []float64{tl, tl + .5} {
+				if inTree {
+					wt[e.LI] = orig + delta
+				} else {
+					wt[e.LI] = orig - delta
+				}
+				d := 0.
+				tree.Edges(func(e graph.LabeledEdge) { d += w(e.LI) })
+				_, min := g.Kruskal(w)
+				if (d == min) != (delta == tl) {
+					t.Fatal("tolerance", e, inTree, tl)
+				}
+			}
+			wt[e.LI] = orig
+		})
+	}
+}
+
+// countSpanningForests counts spanning forests by trying all edge subsets.
+func countSpanningForests(g graph.LabeledUndirected) int {
+	var edges []graph.LabeledEdge
+	g.Edges(func(e graph.LabeledEdge) {
+		if e.N1 != e.N2 {
+			edges = append(edges, e)
+		}
+	})
+	_, nc := g.ConnectedComponentInts()
+	full := g.Order() - nc
+	c := 0
+	for s := 0; s < 1<<uint(len(edges)); s++ {
+		var f graph.LabeledUndirected
+		f.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, g.Order())
+		for j, e := range edges {
+			if s&(1<<uint(j)) != 0 {
+				f.AddEdge(e.Edge, e.LI)
+			}
+		}
+		if _, fc := f.ConnectedComponentInts(); f.Size() == full && fc == nc {
+			c++
+		}
+	}
+	return c
+}
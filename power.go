@@ -0,0 +1,338 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// power.go has centrality measures of directed graphs computed by power
+// iteration: eigenvector centrality, Katz centrality, HITS hub and
+// authority scores, and personalized PageRank.
+//
+// Each iterates until the sum over nodes of absolute changes in score is
+// at most tol times the order of the graph, that is, until the mean
+// change per node is at most tol.  If this does not happen within maxIter
+// iterations, the last scores computed are returned along with an error.
+//
+// Unlabeled versions give each arc weight 1.  Labeled versions take arc
+// weights from a WeightFunc.  Weights must be non-negative.  Parallel arcs
+// add their weights.
+
+// arcWeights calls visit for each arc from node fr.
+type arcWeights func(fr NI, visit func(to NI, wt float64))
+
+func (g AdjacencyList) arcWeights() arcWeights {
+	return func(fr NI, visit func(NI, float64)) {
+		for _, to := range g[fr] {
+			visit(to, 1)
+		}
+	}
+}
+
+func (g LabeledAdjacencyList) arcWeights(w WeightFunc) arcWeights {
+	return func(fr NI, visit func(NI, float64)) {
+		for _, h := range g[fr] {
+			visit(h.To, w(h.Label))
+		}
+	}
+}
+
+// powerIterate repeatedly calls step to compute x1 from x0, until the
+// change is within tolerance.  It returns the final vector and the number
+// of iterations used.
+func powerIterate(x0 []float64, tol float64, maxIter int, step func(x0, x1 []float64)) (x []float64, iter int, err error) {
+	x1 := make([]float64, len(x0))
+	lim := tol * float64(len(x0))
+	for iter < maxIter {
+		step(x0, x1)
+		iter++
+		d := 0.
+		for n, v := range x1 {
+			d += math.Abs(v - x0[n])
+		}
+		x0, x1 = x1, x0
+		if d <= lim {
+			return x0, iter, nil
+		}
+	}
+	return x0, iter, errors.New("power iteration did not converge")
+}
+
+// scaleBy multiplies x by s.
+func scaleBy(x []float64, s float64) {
+	for n := range x {
+		x[n] *= s
+	}
+}
+
+func eigenvector(order int, aw arcWeights, tol float64, maxIter int) ([]float64, int, error) {
+	x0 := make([]float64, order)
+	for n := range x0 {
+		x0[n] = 1 / math.Sqrt(float64(order))
+	}
+	return powerIterate(x0, tol, maxIter, func(x0, x1 []float64) {
+		copy(x1, x0)
+		for fr, xf := range x0 {
+			aw(NI(fr), func(to NI, wt float64) { x1[to] += xf * wt })
+		}
+		s := 0.
+		for _, v := range x1 {
+			s += v * v
+		}
+		scaleBy(x1, 1/math.Sqrt(s))
+	})
+}
+
+func katz(order int, aw arcWeights, alpha, beta, tol float64, maxIter int) ([]float64, int, error) {
+	return powerIterate(make([]float64, order), tol, maxIter, func(x0, x1 []float64) {
+		for n := range x1 {
+			x1[n] = beta
+		}
+		for fr, xf := range x0 {
+			aw(NI(fr), func(to NI, wt float64) { x1[to] += alpha * xf * wt })
+		}
+	})
+}
+
+func hits(order int, aw arcWeights, tol float64, maxIter int) (hubs, authorities []float64, iter int, err error) {
+	authorities = make([]float64, order)
+	h0 := make([]float64, order)
+	for n := range h0 {
+		h0[n] = 1 / float64(order)
+	}
+	// normalize to max 1, returning false if all values are 0
+	maxNorm := func(x []float64) bool {
+		m := 0.
+		for _, v := range x {
+			m = math.Max(m, v)
+		}
+		if m == 0 {
+			return false
+		}
+		scaleBy(x, 1/m)
+		return true
+	}
+	empty := false
+	hubs, iter, err = powerIterate(h0, tol, maxIter, func(h0, h1 []float64) {
+		for n := range authorities {
+			authorities[n] = 0
+		}
+		for fr, hf := range h0 {
+			aw(NI(fr), func(to NI, wt float64) { authorities[to] += hf * wt })
+		}
+		for fr := range h1 {
+			s := 0.
+			aw(NI(fr), func(to NI, wt float64) { s += authorities[to] * wt })
+			h1[fr] = s
+		}
+		if !maxNorm(h1) {
+			empty = true
+			copy(h1, h0) // converge immediately
+		}
+	})
+	if empty {
+		// no arcs of positive weight.  all scores are 0.
+		for n := range hubs {
+			hubs[n] = 0
+			authorities[n] = 0
+		}
+		return
+	}
+	// authorities corresponding to the final hubs, then both to sum 1.
+	for n := range authorities {
+		authorities[n] = 0
+	}
+	for fr, hf := range hubs {
+		aw(NI(fr), func(to NI, wt float64) { authorities[to] += hf * wt })
+	}
+	for _, x := range [][]float64{hubs, authorities} {
+		s := 0.
+		for _, v := range x {
+			s += v
+		}
+		scaleBy(x, 1/s)
+	}
+	return
+}
+
+func pageRank(order int, aw arcWeights, d float64, p []float64, tol float64, maxIter int) ([]float64, int, error) {
+	if p == nil {
+		p = make([]float64, order)
+		for n := range p {
+			p[n] = 1
+		}
+	}
+	if len(p) != order {
+		return nil, 0, errors.New("personalization length not order of graph")
+	}
+	// normalized personalization and total out-weight of each node
+	ps := 0.
+	for _, v := range p {
+		if v < 0 {
+			return nil, 0, errors.New("negative personalization")
+		}
+		ps += v
+	}
+	if ps == 0 && order > 0 {
+		return nil, 0, errors.New("zero personalization")
+	}
+	pn := make([]float64, order)
+	out := make([]float64, order)
+	for n, v := range p {
+		pn[n] = v / ps
+		aw(NI(n), func(_ NI, wt float64) { out[n] += wt })
+	}
+	x0 := make([]float64, order)
+	for n := range x0 {
+		x0[n] = 1 / float64(order)
+	}
+	return powerIterate(x0, tol, maxIter, func(x0, x1 []float64) {
+		dangling := 0.
+		for n, o := range out {
+			if o == 0 {
+				dangling += x0[n]
+			}
+		}
+		for n, v := range pn {
+			x1[n] = (1 - d + d*dangling) * v
+		}
+		for fr, xf := range x0 {
+			if o := out[fr]; o > 0 {
+				f := d * xf / o
+				aw(NI(fr), func(to NI, wt float64) { x1[to] += f * wt })
+			}
+		}
+	})
+}
+
+// Eigenvector computes eigenvector centrality for each node of a directed
+// graph.
+//
+// The eigenvector centrality of a node is proportional to the sum of the
+// centralities of nodes with arcs to it.  The scores are the components of
+// the principal left eigenvector of the adjacency matrix.  For a graph that
+// is not strongly connected, scores of nodes not reachable from the
+// dominant strongly connected components tend to 0.
+//
+// Iteration uses the adjacency matrix plus the identity matrix, which has
+// the same eigenvectors but avoids oscillation for periodic graphs such as
+// bipartite graphs.  The result is normalized to unit Euclidean length.
+//
+// Iteration stops when the mean absolute change per node is at most tol,
+// or after maxIter iterations.  Returned are the scores and the number of
+// iterations used.  A non-nil error is returned if scores did not converge
+// within maxIter iterations.
+func (g Directed) Eigenvector(tol float64, maxIter int) (x []float64, iter int, err error) {
+	a := g.AdjacencyList
+	return eigenvector(len(a), a.arcWeights(), tol, maxIter)
+}
+
+// Eigenvector computes eigenvector centrality for each node of a directed
+// graph with weighted arcs.
+//
+// Arc weights are given by WeightFunc w and must be non-negative.
+// Otherwise the method is the same as Directed.Eigenvector.
+func (g LabeledDirected) Eigenvector(w WeightFunc, tol float64, maxIter int) (x []float64, iter int, err error) {
+	a := g.LabeledAdjacencyList
+	return eigenvector(len(a), a.arcWeights(w), tol, maxIter)
+}
+
+// Katz computes Katz centrality for each node of a directed graph.
+//
+// The Katz centrality of a node is alpha times the sum of the centralities
+// of nodes with arcs to it, plus beta.  Equivalently it counts walks ending
+// at the node, where walks of length k are attenuated by alpha^k.
+//
+// Alpha must be less than the reciprocal of the largest eigenvalue of the
+// adjacency matrix for the iteration to converge.  Beta is commonly 1.
+// Results are not normalized.
+//
+// Iteration stops when the mean absolute change per node is at most tol,
+// or after maxIter iterations.  Returned are the scores and the number of
+// iterations used.  A non-nil error is returned if scores did not converge
+// within maxIter iterations.
+func (g Directed) Katz(alpha, beta, tol float64, maxIter int) (x []float64, iter int, err error) {
+	a := g.AdjacencyList
+	return katz(len(a), a.arcWeights(), alpha, beta, tol, maxIter)
+}
+
+// Katz computes Katz centrality for each node of a directed graph with
+// weighted arcs.
+//
+// Arc weights are given by WeightFunc w and must be non-negative.
+// Otherwise the method is the same as Directed.Katz.
+func (g LabeledDirected) Katz(w WeightFunc, alpha, beta, tol float64, maxIter int) (x []float64, iter int, err error) {
+	a := g.LabeledAdjacencyList
+	return katz(len(a), a.arcWeights(w), alpha, beta, tol, maxIter)
+}
+
+// HITS computes hub and authority scores for each node of a directed graph
+// by Kleinberg's hyperlink-induced topic search algorithm.
+//
+// The authority score of a node is proportional to the sum of hub scores
+// of nodes with arcs to it.  The hub score of a node is proportional to the
+// sum of authority scores of nodes it has arcs to.  Hub and authority
+// scores are each normalized to sum to 1.  If g has no arcs, all scores
+// are 0.
+//
+// Iteration stops when the mean absolute change per node in hub scores,
+// normalized to a maximum of 1, is at most tol, or after maxIter
+// iterations.  Returned are the scores and the number of iterations used.
+// A non-nil error is returned if scores did not converge within maxIter
+// iterations.
+func (g Directed) HITS(tol float64, maxIter int) (hubs, authorities []float64, iter int, err error) {
+	a := g.AdjacencyList
+	return hits(len(a), a.arcWeights(), tol, maxIter)
+}
+
+// HITS computes hub and authority scores for each node of a directed graph
+// with weighted arcs.
+//
+// Arc weights are given by WeightFunc w and must be non-negative.
+// Otherwise the method is the same as Directed.HITS.
+func (g LabeledDirected) HITS(w WeightFunc, tol float64, maxIter int) (hubs, authorities []float64, iter int, err error) {
+	a := g.LabeledAdjacencyList
+	return hits(len(a), a.arcWeights(w), tol, maxIter)
+}
+
+// PersonalizedPageRank computes PageRank scores for each node of a directed
+// graph, optionally personalized to favor certain nodes.
+//
+// The PageRank score of a node is the stationary probability of a random
+// walk that at each step, with probability d, follows an arc chosen
+// uniformly from the current node, and otherwise teleports to a node
+// chosen according to personalization vector p.  Argument d is a damping
+// factor; reportedly a value of .85 works well.  If p is nil, teleports
+// are uniform over all nodes, giving standard PageRank.  Otherwise p must
+// have length equal to the order of g and non-negative values, not all
+// zero.  P need not be normalized.
+//
+// A walk at a node with no out-arcs, a dangling node, always teleports.
+// Rank is thus not lost at dangling nodes and scores sum to 1.
+//
+// Iteration stops when the mean absolute change per node is at most tol,
+// or after maxIter iterations.  Returned are the scores and the number of
+// iterations used.  A non-nil error is returned if p is invalid or if
+// scores did not converge within maxIter iterations.
+//
+// See also PageRank, a simpler function that runs a fixed number of
+// iterations and does not handle dangling nodes.
+func (g Directed) PersonalizedPageRank(d float64, p []float64, tol float64, maxIter int) (pr []float64, iter int, err error) {
+	a := g.AdjacencyList
+	return pageRank(len(a), a.arcWeights(), d, p, tol, maxIter)
+}
+
+// PersonalizedPageRank computes PageRank scores for each node of a directed
+// graph with weighted arcs.
+//
+// Arc weights are given by WeightFunc w and must be non-negative.  The
+// random walk follows an arc with probability proportional to its weight.
+// A node with no out-arcs of positive weight is a dangling node.
+// Otherwise the method is the same as Directed.PersonalizedPageRank.
+func (g LabeledDirected) PersonalizedPageRank(w WeightFunc, d float64, p []float64, tol float64, maxIter int) (pr []float64, iter int, err error) {
+	a := g.LabeledAdjacencyList
+	return pageRank(len(a), a.arcWeights(w), d, p, tol, maxIter)
+}
@@ -0,0 +1,178 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_Eigenvector() {
+	// 0 <--> 1 <--> 2
+	//         \    ^
+	//          v  /
+	//           3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {0, 2, 3},
+		2: {1},
+		3: {2},
+	}}
+	x, _, err := g.Eigenvector(1e-9, 1000)
+	fmt.Printf("%.3f %v\n", x, err)
+	// Output:
+	// [0.372 0.602 0.602 0.372] <nil>
+}
+
+func ExampleDirected_Katz() {
+	// 0 --> 1 --> 2
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {},
+	}}
+	x, iter, err := g.Katz(.5, 1, 1e-9, 100)
+	fmt.Println(x, iter, err)
+	// Output:
+	// [1 1.5 1.75] 4 <nil>
+}
+
+func ExampleDirected_HITS() {
+	// nodes 0 and 1 are hubs, with arcs to authorities 2, 3, and 4.
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2, 3},
+		1: {2, 3, 4},
+		4: {},
+	}}
+	h, a, _, err := g.HITS(1e-12, 100)
+	fmt.Printf("%.3f\n%.3f\n%v\n", h, a, err)
+	// Output:
+	// [0.438 0.562 0.000 0.000 0.000]
+	// [0.000 0.000 0.390 0.390 0.219]
+	// <nil>
+}
+
+func TestPowerNotConverged(t *testing.T) {
+	// 0 <--> 1, largest eigenvalue 1
+	g := graph.Directed{graph.AdjacencyList{{1}, {0}}}
+	if _, iter, err := g.Katz(1.5, 1, 1e-6, 50); err == nil || iter != 50 {
+		t.Fatal("Katz converged", iter)
+	}
+	if _, _, err := g.Katz(.5, 1, 1e-6, 50); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPowerLabeled(t *testing.T) {
+	// labeled versions with unit weights match unlabeled, labeled versions
+	// with weight 2 scale as expected.
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}},
+		1: {{To: 2}},
+		2: {{To: 0}, {To: 3}},
+		3: {{To: 0}},
+	}}
+	u := g.Unlabeled()
+	one := func(graph.LI) float64 { return 1 }
+	two := func(graph.LI) float64 { return 2 }
+	same := func(a, b []float64) bool {
+		for i := range a {
+			if math.Abs(a[i]-b[i]) > 1e-6 {
+				return false
+			}
+		}
+		return true
+	}
+	e1, _, err := u.Eigenvector(1e-12, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e2, _, _ := g.Eigenvector(two, 1e-12, 1000)
+	if !same(e1, e2) {
+		t.Fatal("Eigenvector", e1, e2)
+	}
+	k1, _, _ := u.Katz(.1, 1, 1e-12, 1000)
+	k2, _, _ := g.Katz(two, .05, 1, 1e-12, 1000)
+	if !same(k1, k2) {
+		t.Fatal("Katz", k1, k2)
+	}
+	h1, a1, _, _ := u.HITS(1e-12, 1000)
+	h2, a2, _, _ := g.HITS(one, 1e-12, 1000)
+	if !same(h1, h2) || !same(a1, a2) {
+		t.Fatal("HITS", h1, h2, a1, a2)
+	}
+}
+
+func ExampleDirected_PersonalizedPageRank() {
+	// 0 --> 1 --> 2
+	//  ^         /
+	//   \-------/
+	//
+	// node 3 --> 2, node 4 is dangling
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {0},
+		3: {2},
+		4: {},
+	}}
+	pr, _, err := g.PersonalizedPageRank(.85, nil, 1e-9, 200)
+	fmt.Printf("%.3f %v\n", pr, err)
+	pr, _, err = g.PersonalizedPageRank(.85, []float64{0, 0, 0, 1, 0}, 1e-9, 200)
+	fmt.Printf("%.3f %v\n", pr, err)
+	// Output:
+	// [0.309 0.298 0.321 0.036 0.036] <nil>
+	// [0.281 0.239 0.330 0.150 0.000] <nil>
+}
+
+func TestPersonalizedPageRank(t *testing.T) {
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 2, Label: 3}},
+		1: {{To: 2, Label: 1}},
+		2: {{To: 0, Label: 2}},
+		3: {{To: 0, Label: 1}, {To: 4, Label: 0}},
+		4: {},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	p := []float64{1, 0, 2, 0, 1}
+	const d = .7
+	pr, _, err := g.PersonalizedPageRank(w, d, p, 1e-13, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// check stationary equation directly
+	want := make([]float64, len(pr))
+	dangling := pr[4] // node 4 has no arcs, node 3 arc to 4 has weight 0
+	for n := range want {
+		want[n] = (1 - d + d*dangling) * p[n] / 4
+	}
+	for fr, to := range g.LabeledAdjacencyList {
+		out := 0.
+		for _, h := range to {
+			out += w(h.Label)
+		}
+		for _, h := range to {
+			want[h.To] += d * pr[fr] * w(h.Label) / out
+		}
+	}
+	sum := 0.
+	for n, x := range pr {
+		sum += x
+		if math.Abs(x-want[n]) > 1e-9 {
+			t.Fatal(pr, want)
+		}
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Fatal("sum", sum)
+	}
+	if _, _, err := g.PersonalizedPageRank(w, d, p[1:], 1e-9, 10); err == nil {
+		t.Fatal("short personalization accepted")
+	}
+	if _, iter, err := g.PersonalizedPageRank(w, d, nil, 0, 3); err == nil || iter != 3 {
+		t.Fatal("converged with tol 0")
+	}
+}
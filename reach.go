@@ -0,0 +1,169 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "math/rand"
+
+// reach.go has a reachability index for directed graphs.
+//
+// TransitiveClosure answers reachability queries in constant time but takes
+// n² bits.  The index here takes memory linear in the size of the graph.
+// Queries are answered by interval labels in constant time when the answer
+// is no, as it is for most pairs of nodes in large sparse graphs, and
+// otherwise by a search pruned by the labels.
+
+// ReachIndex is an index for reachability queries on a directed graph.
+//
+// The index is that of GRAIL by Yildirim, Chaoji, and Zaki.  Strongly
+// connected components are condensed to nodes of a DAG.  Each DAG node is
+// labeled with an interval from each of several randomized depth-first
+// traversals, and with its topological level.  If node a reaches node b
+// then the intervals of a contain those of b and the level of a is less
+// than that of b.
+//
+// Construct with Directed.ReachIndex.
+type ReachIndex struct {
+	comp  []NI          // DAG node of each graph node
+	dag   AdjacencyList // condensation
+	level []int32       // longest path from a source of the DAG
+	lo    [][]int32     // lo[i][c] is the least post-order rank below c
+	hi    [][]int32     // hi[i][c] is the post-order rank of c
+}
+
+// ReachIndex constructs a reachability index for directed graph g.
+//
+// Argument d is the number of randomized traversals, each giving an
+// interval label for each node.  More intervals take more memory but
+// answer more queries without a search.  Values of 2 to 5 are typical.
+// Argument d must be at least 1.
+//
+// Traversal orders are random.  For reproducible results, pass a seeded
+// *rand.Rand.  If rr is nil, the rand package default shared source is used.
+//
+// Construction takes O(d(n+m)) time, where m is the number of arcs between
+// strongly connected components, and the index takes O(dn+m) memory.
+func (g Directed) ReachIndex(d int, rr *rand.Rand) *ReachIndex {
+	ri, perm := rand.Intn, rand.Perm
+	if rr != nil {
+		ri, perm = rr.Intn, rr.Perm
+	}
+	scc, cd := g.Condensation()
+	x := &ReachIndex{
+		comp:  make([]NI, g.Order()),
+		dag:   cd,
+		level: make([]int32, len(cd)),
+		lo:    make([][]int32, d),
+		hi:    make([][]int32, d),
+	}
+	for c, ns := range scc {
+		for _, n := range ns {
+			x.comp[n] = NI(c)
+		}
+	}
+	// condensation components are in reverse topological order
+	for c := len(cd) - 1; c >= 0; c-- {
+		for _, to := range cd[c] {
+			if l := x.level[c] + 1; l > x.level[to] {
+				x.level[to] = l
+			}
+		}
+	}
+	// randomized post-order traversals.  children are visited in order
+	// from a random offset.
+	type frame struct {
+		c     NI
+		start int // random offset into cd[c]
+		i     int // number of children visited
+	}
+	var stack []frame
+	for i := range x.lo {
+		lo := make([]int32, len(cd))
+		hi := make([]int32, len(cd)) // 0 until visited
+		rank := int32(0)
+		push := func(c NI) {
+			s := 0
+			if len(cd[c]) > 0 {
+				s = ri(len(cd[c]))
+			}
+			stack = append(stack, frame{c, s, 0})
+			hi[c] = -1 // visiting
+		}
+		for _, root := range perm(len(cd)) {
+			if hi[root] != 0 {
+				continue
+			}
+			push(NI(root))
+			for len(stack) > 0 {
+				f := &stack[len(stack)-1]
+				if to := cd[f.c]; f.i < len(to) {
+					ch := to[(f.start+f.i)%len(to)]
+					f.i++
+					if hi[ch] == 0 {
+						push(ch)
+					}
+					continue
+				}
+				c := f.c
+				stack = stack[:len(stack)-1]
+				rank++
+				hi[c] = rank
+				lo[c] = rank
+				for _, ch := range cd[c] {
+					if lo[ch] < lo[c] {
+						lo[c] = lo[ch]
+					}
+				}
+			}
+		}
+		x.lo[i], x.hi[i] = lo, hi
+	}
+	return x
+}
+
+// contains returns true if the intervals of DAG node c contain those of
+// DAG node t and the level of c is less than that of t, that is, if c may
+// reach t.
+func (x *ReachIndex) contains(c, t NI) bool {
+	if x.level[c] >= x.level[t] {
+		return false
+	}
+	for i, lo := range x.lo {
+		if lo[t] < lo[c] || x.hi[i][t] > x.hi[i][c] {
+			return false
+		}
+	}
+	return true
+}
+
+// Reaches returns true if there is a path from node a to node b.
+//
+// A node reaches itself by a path of no arcs, unlike the result of
+// TransitiveClosure where a node reaches itself only if it is on a cycle.
+// Reaches is safe for concurrent use.
+func (x *ReachIndex) Reaches(a, b NI) bool {
+	ca, cb := x.comp[a], x.comp[b]
+	if ca == cb {
+		return true
+	}
+	if !x.contains(ca, cb) {
+		return false
+	}
+	// search pruned by labels
+	seen := map[NI]bool{ca: true}
+	stack := []NI{ca}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, to := range x.dag[c] {
+			if to == cb {
+				return true
+			}
+			if !seen[to] && x.contains(to, cb) {
+				seen[to] = true
+				stack = append(stack, to)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_ReachIndex() {
+	//     1-->2----
+	//     ^   |   |
+	//  0  |   v   v
+	//     ----3-->4-->5<=>7
+	//             |   ^
+	//             |   |
+	//             --->6<=>8
+	g := graph.Directed{graph.AdjacencyList{
+		1: {2},
+		2: {3, 4},
+		3: {1, 4},
+		4: {5, 6},
+		5: {7},
+		6: {5, 8},
+		7: {5},
+		8: {6},
+	}}
+	x := g.ReachIndex(2, rand.New(rand.NewSource(1)))
+	fmt.Println(x.Reaches(1, 8))
+	fmt.Println(x.Reaches(8, 1))
+	fmt.Println(x.Reaches(7, 6))
+	fmt.Println(x.Reaches(6, 7))
+	fmt.Println(x.Reaches(0, 1))
+	// Output:
+	// true
+	// false
+	// false
+	// true
+	// false
+}
+
+func TestReachIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for tc := 0; tc < 100; tc++ {
+		n := 1 + r.Intn(40)
+		g := graph.GnmDirected(n, r.Intn(2*n), r)
+		cl := g.TransitiveClosure()
+		x := g.ReachIndex(1+r.Intn(3), r)
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				want := a == b || cl[a].Bit(b) == 1
+				if got := x.Reaches(graph.NI(a), graph.NI(b)); got != want {
+					t.Fatalf("Reaches(%d, %d) = %t, want %t", a, b, got, want)
+				}
+			}
+		}
+	}
+}
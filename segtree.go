@@ -0,0 +1,250 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "math"
+
+// segtree.go has a segment tree with lazy range updates, used with
+// HeavyLight for path and subtree queries.
+
+// Aggregate defines an associative aggregate function for a SegmentTree
+// and, optionally, a range update operation compatible with it.
+//
+// Combine must be associative and Identity must be an identity element
+// for Combine.
+//
+// Update and Compose are needed only for SegmentTree.Apply.  Update returns
+// the aggregate of n values after update u has been applied to each, given
+// their aggregate a before the update.  Compose returns a single update
+// equivalent to applying u1 followed by u2.
+//
+// Variables SumAdd, MinAdd, and MaxAdd give common aggregates.
+type Aggregate struct {
+	Combine  func(a, b float64) float64
+	Identity float64
+	Update   func(a, u float64, n int) float64
+	Compose  func(u1, u2 float64) float64
+}
+
+func addUpdates(u1, u2 float64) float64 { return u1 + u2 }
+
+// SumAdd aggregates by summing values.  Updates add a value.
+var SumAdd = Aggregate{
+	Combine:  func(a, b float64) float64 { return a + b },
+	Identity: 0,
+	Update:   func(a, u float64, n int) float64 { return a + u*float64(n) },
+	Compose:  addUpdates,
+}
+
+// MinAdd aggregates by taking the minimum value.  Updates add a value.
+var MinAdd = Aggregate{
+	Combine: func(a, b float64) float64 {
+		if b < a {
+			return b
+		}
+		return a
+	},
+	Identity: math.Inf(1),
+	Update:   func(a, u float64, n int) float64 { return a + u },
+	Compose:  addUpdates,
+}
+
+// MaxAdd aggregates by taking the maximum value.  Updates add a value.
+var MaxAdd = Aggregate{
+	Combine: func(a, b float64) float64 {
+		if b > a {
+			return b
+		}
+		return a
+	},
+	Identity: math.Inf(-1),
+	Update:   func(a, u float64, n int) float64 { return a + u },
+	Compose:  addUpdates,
+}
+
+// SegmentTree holds a sequence of values and supports queries for the
+// aggregate of a range of values and updates of a range of values.
+//
+// Queries and updates take O(log n) time.
+//
+// Construct with NewSegmentTree or HeavyLight.SegmentTree.
+type SegmentTree struct {
+	agg  Aggregate
+	n    int
+	t    []float64 // aggregate of each tree node
+	r    []float64 // aggregate of each tree node in reverse order
+	lazy []float64 // pending update for children of each tree node
+	has  []bool    // true where lazy holds a pending update
+}
+
+// NewSegmentTree constructs a SegmentTree holding a copy of values,
+// aggregated with agg.
+func NewSegmentTree(values []float64, agg Aggregate) *SegmentTree {
+	s := &SegmentTree{agg: agg, n: len(values)}
+	if s.n == 0 {
+		return s
+	}
+	sz := 1
+	for sz < s.n {
+		sz <<= 1
+	}
+	s.t = make([]float64, 2*sz)
+	s.r = make([]float64, 2*sz)
+	s.lazy = make([]float64, 2*sz)
+	s.has = make([]bool, 2*sz)
+	s.build(1, 0, s.n, values)
+	return s
+}
+
+// Len returns the number of values in s.
+func (s *SegmentTree) Len() int {
+	return s.n
+}
+
+func (s *SegmentTree) build(x, lo, hi int, values []float64) {
+	if hi-lo == 1 {
+		s.t[x] = values[lo]
+		s.r[x] = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	s.build(2*x, lo, mid, values)
+	s.build(2*x+1, mid, hi, values)
+	s.pull(x)
+}
+
+// pull recomputes the aggregates of tree node x from its children.
+func (s *SegmentTree) pull(x int) {
+	s.t[x] = s.agg.Combine(s.t[2*x], s.t[2*x+1])
+	s.r[x] = s.agg.Combine(s.r[2*x+1], s.r[2*x])
+}
+
+// apply applies update u to tree node x covering n values.
+func (s *SegmentTree) apply(x int, u float64, n int) {
+	s.t[x] = s.agg.Update(s.t[x], u, n)
+	s.r[x] = s.agg.Update(s.r[x], u, n)
+	if n > 1 {
+		if s.has[x] {
+			s.lazy[x] = s.agg.Compose(s.lazy[x], u)
+		} else {
+			s.lazy[x], s.has[x] = u, true
+		}
+	}
+}
+
+// push moves a pending update at tree node x down to its children.
+func (s *SegmentTree) push(x, lo, mid, hi int) {
+	if s.has[x] {
+		s.apply(2*x, s.lazy[x], mid-lo)
+		s.apply(2*x+1, s.lazy[x], hi-mid)
+		s.has[x] = false
+	}
+}
+
+// Query returns the aggregate of values in the half-open range [lo, hi).
+//
+// Values are combined in order.  Query returns agg.Identity for an empty
+// range.
+func (s *SegmentTree) Query(lo, hi int) float64 {
+	if lo >= hi {
+		return s.agg.Identity
+	}
+	return s.query(1, 0, s.n, lo, hi)
+}
+
+func (s *SegmentTree) query(x, nlo, nhi, lo, hi int) float64 {
+	if lo <= nlo && nhi <= hi {
+		return s.t[x]
+	}
+	mid := (nlo + nhi) / 2
+	s.push(x, nlo, mid, nhi)
+	switch {
+	case hi <= mid:
+		return s.query(2*x, nlo, mid, lo, hi)
+	case lo >= mid:
+		return s.query(2*x+1, mid, nhi, lo, hi)
+	}
+	return s.agg.Combine(s.query(2*x, nlo, mid, lo, hi),
+		s.query(2*x+1, mid, nhi, lo, hi))
+}
+
+// QueryReverse returns the aggregate of values in the half-open range
+// [lo, hi), combined in reverse order, from hi-1 down to lo.
+//
+// The result is the same as that of Query if the Combine function of the
+// Aggregate is commutative.  QueryReverse returns agg.Identity for an empty
+// range.
+func (s *SegmentTree) QueryReverse(lo, hi int) float64 {
+	if lo >= hi {
+		return s.agg.Identity
+	}
+	return s.queryReverse(1, 0, s.n, lo, hi)
+}
+
+func (s *SegmentTree) queryReverse(x, nlo, nhi, lo, hi int) float64 {
+	if lo <= nlo && nhi <= hi {
+		return s.r[x]
+	}
+	mid := (nlo + nhi) / 2
+	s.push(x, nlo, mid, nhi)
+	switch {
+	case hi <= mid:
+		return s.queryReverse(2*x, nlo, mid, lo, hi)
+	case lo >= mid:
+		return s.queryReverse(2*x+1, mid, nhi, lo, hi)
+	}
+	return s.agg.Combine(s.queryReverse(2*x+1, mid, nhi, lo, hi),
+		s.queryReverse(2*x, nlo, mid, lo, hi))
+}
+
+// Get returns the value at index i.
+func (s *SegmentTree) Get(i int) float64 {
+	return s.query(1, 0, s.n, i, i+1)
+}
+
+// Set sets the value at index i to v.
+func (s *SegmentTree) Set(i int, v float64) {
+	s.set(1, 0, s.n, i, v)
+}
+
+func (s *SegmentTree) set(x, lo, hi, i int, v float64) {
+	if hi-lo == 1 {
+		s.t[x] = v
+		s.r[x] = v
+		return
+	}
+	mid := (lo + hi) / 2
+	s.push(x, lo, mid, hi)
+	if i < mid {
+		s.set(2*x, lo, mid, i, v)
+	} else {
+		s.set(2*x+1, mid, hi, i, v)
+	}
+	s.pull(x)
+}
+
+// Apply applies update u to each value in the half-open range [lo, hi).
+//
+// The Aggregate of s must have Update and Compose functions.
+func (s *SegmentTree) Apply(lo, hi int, u float64) {
+	if lo < hi {
+		s.update(1, 0, s.n, lo, hi, u)
+	}
+}
+
+func (s *SegmentTree) update(x, nlo, nhi, lo, hi int, u float64) {
+	if lo <= nlo && nhi <= hi {
+		s.apply(x, u, nhi-nlo)
+		return
+	}
+	mid := (nlo + nhi) / 2
+	s.push(x, nlo, mid, nhi)
+	if lo < mid {
+		s.update(2*x, nlo, mid, lo, hi, u)
+	}
+	if hi > mid {
+		s.update(2*x+1, mid, nhi, lo, hi, u)
+	}
+	s.pull(x)
+}
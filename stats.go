@@ -0,0 +1,358 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// stats.go has a summary of descriptive statistics of a graph.
+
+// DegreeStats summarizes a degree distribution.
+type DegreeStats struct {
+	Min, Max       int
+	Mean, Variance float64
+	// Distribution is the number of nodes of each degree, indexed by
+	// degree, with length Max+1.
+	Distribution []int
+}
+
+func degreeStats(deg []int) (s DegreeStats) {
+	if len(deg) == 0 {
+		return
+	}
+	s.Min = deg[0]
+	for _, d := range deg {
+		if d < s.Min {
+			s.Min = d
+		}
+		if d > s.Max {
+			s.Max = d
+		}
+		s.Mean += float64(d)
+	}
+	s.Mean /= float64(len(deg))
+	s.Distribution = make([]int, s.Max+1)
+	for _, d := range deg {
+		s.Distribution[d]++
+		x := float64(d) - s.Mean
+		s.Variance += x * x
+	}
+	s.Variance /= float64(len(deg))
+	return
+}
+
+// Statistics summarizes a graph with commonly reported descriptive
+// statistics.
+//
+// Statistics are computed by Directed.Statistics and Undirected.Statistics.
+type Statistics struct {
+	Directed bool
+	Order    int
+	Size     int     // number of edges, or arcs for a directed graph
+	Density  float64 // by Density or ArcDensity, 0 for fewer than 2 nodes
+	// Degree is the degree distribution for an undirected graph or the
+	// out-degree distribution for a directed graph.
+	Degree DegreeStats
+	// InDegree is the in-degree distribution of a directed graph.
+	InDegree DegreeStats
+	// Assortativity is the degree assortativity coefficient, NaN if
+	// undefined.
+	Assortativity float64
+	// Components is the number of connected components, or weakly
+	// connected components of a directed graph.  LargestComponent is the
+	// number of nodes in the largest of these.
+	Components       int
+	LargestComponent int
+	// LargestStrongComponent is the number of nodes in the largest
+	// strongly connected component of a directed graph.
+	LargestStrongComponent int
+	// Diameter and Radius are the maximum and minimum eccentricities of
+	// nodes in the largest connected component, or largest strongly
+	// connected component of a directed graph.
+	Diameter, Radius int
+	// Samples is the number of nodes from which eccentricities were
+	// computed if Diameter and Radius are estimates, 0 if they are exact.
+	Samples int
+}
+
+// assortativity returns the Pearson correlation of x and y degrees over
+// all arcs of a.
+func assortativity(a AdjacencyList, xd, yd []int) float64 {
+	var n, sx, sy, sxx, syy, sxy float64
+	for fr, to := range a {
+		x := float64(xd[fr])
+		for _, to := range to {
+			y := float64(yd[to])
+			n++
+			sx += x
+			sy += y
+			sxx += x * x
+			syy += y * y
+			sxy += x * y
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	vx := sxx/n - (sx/n)*(sx/n)
+	vy := syy/n - (sy/n)*(sy/n)
+	if vx <= 0 || vy <= 0 {
+		return math.NaN()
+	}
+	return (sxy/n - sx/n*sy/n) / math.Sqrt(vx*vy)
+}
+
+// eccentricityRange computes the minimum and maximum eccentricities of
+// nodes in component c, with searches restricted to nodes n with
+// comp[n] == c.  Argument tr is the transpose of a for a directed graph, or
+// nil for an undirected graph.
+//
+// If samples is > 0 and less than the number of nodes in the component,
+// eccentricities are computed only for that many randomly chosen nodes and
+// sampled is returned as samples.  Otherwise sampled is 0 and the results
+// are exact, computed by eccentricity bounding as in Extremes.
+func eccentricityRange(a, tr AdjacencyList, comp []int, c, samples int, rr *rand.Rand) (min, max, sampled int) {
+	// nodes of the component, and the index of each in nodes
+	var nodes []NI
+	local := make([]int, len(a))
+	for n, cn := range comp {
+		if cn == c {
+			local[n] = len(nodes)
+			nodes = append(nodes, NI(n))
+		}
+	}
+	if len(nodes) == 0 {
+		return
+	}
+	// bfs computes distances from nodes[u] in dist, indexed as nodes.
+	// only component nodes are reset and searched.
+	var frontier, next []NI
+	bfs := func(adj AdjacencyList, u int, dist []float64) {
+		for i := range dist {
+			dist[i] = -1
+		}
+		dist[u] = 0
+		frontier = append(frontier[:0], nodes[u])
+		for d := 1.; len(frontier) > 0; d++ {
+			next = next[:0]
+			for _, n := range frontier {
+				for _, to := range adj[n] {
+					if comp[to] == c && dist[local[to]] < 0 {
+						dist[local[to]] = d
+						next = append(next, to)
+					}
+				}
+			}
+			frontier, next = next, frontier
+		}
+	}
+	if samples <= 0 || samples >= len(nodes) {
+		deg := make([]int, len(nodes))
+		for i, n := range nodes {
+			deg[i] = len(a[n])
+			if tr != nil {
+				deg[i] += len(tr[n])
+			}
+		}
+		x, _ := boundExtremes(deg, func(u NI, fwd, bwd []float64) bool {
+			bfs(a, int(u), fwd)
+			if tr == nil {
+				copy(bwd, fwd)
+			} else {
+				bfs(tr, int(u), bwd)
+			}
+			return true // all nodes reached within a component
+		})
+		return int(x.Radius), int(x.Diameter), 0
+	}
+	perm := rand.Perm
+	if rr != nil {
+		perm = rr.Perm
+	}
+	dist := make([]float64, len(nodes))
+	min = len(a)
+	for _, u := range perm(len(nodes))[:samples] {
+		bfs(a, u, dist)
+		e := 0
+		for _, d := range dist {
+			if int(d) > e {
+				e = int(d)
+			}
+		}
+		if e < min {
+			min = e
+		}
+		if e > max {
+			max = e
+		}
+	}
+	return min, max, samples
+}
+
+// Statistics computes summary statistics of an undirected graph.
+//
+// Diameter and Radius are computed for the largest connected component.
+// If samples is > 0 and less than the size of the component, they are
+// estimated with a breadth-first search from that many randomly chosen
+// nodes.  Otherwise they are exact, computed by the eccentricity bounding
+// algorithm used by Extremes.  Sampled values bound the exact values,
+// sampled Diameter is a lower bound and sampled Radius is an upper bound.
+// For reproducible results, pass a seeded *rand.Rand.  If rr is nil, the
+// rand package default shared source is used.
+//
+// Degrees count loops twice as in Degree.  Assortativity is the degree
+// correlation coefficient of Newman, the Pearson correlation of the
+// degrees at either end of an edge.
+//
+// See Statistics.String for a text formatter.
+func (g Undirected) Statistics(samples int, rr *rand.Rand) Statistics {
+	a := g.AdjacencyList
+	s := Statistics{Order: len(a), Size: g.Size()}
+	if len(a) > 1 {
+		s.Density = g.Density()
+	}
+	deg := make([]int, len(a))
+	for n := range a {
+		deg[n] = g.Degree(NI(n))
+	}
+	s.Degree = degreeStats(deg)
+	s.Assortativity = assortativity(a, deg, deg)
+	ci, nc := g.ConnectedComponentInts()
+	s.Components = nc
+	order := make([]int, nc+1)
+	big := 0
+	for _, c := range ci {
+		order[c]++
+		if order[c] > s.LargestComponent {
+			s.LargestComponent = order[c]
+			big = c
+		}
+	}
+	s.Radius, s.Diameter, s.Samples =
+		eccentricityRange(a, nil, ci, big, samples, rr)
+	return s
+}
+
+// Statistics computes summary statistics of a directed graph.
+//
+// Degree holds statistics of out-degrees and InDegree of in-degrees.
+// Assortativity is the out-in degree correlation coefficient, the Pearson
+// correlation of the out-degree of the start node and the in-degree of the
+// end node of each arc.
+//
+// Diameter and Radius are computed within the largest strongly connected
+// component, where all eccentricities are finite.  Otherwise the method is
+// the same as Undirected.Statistics.
+func (g Directed) Statistics(samples int, rr *rand.Rand) Statistics {
+	a := g.AdjacencyList
+	s := Statistics{Directed: true, Order: len(a), Size: a.ArcSize()}
+	if len(a) > 1 {
+		s.Density = a.ArcDensity()
+	}
+	outd := make([]int, len(a))
+	for n, to := range a {
+		outd[n] = len(to)
+	}
+	ind := g.InDegree()
+	s.Degree = degreeStats(outd)
+	s.InDegree = degreeStats(ind)
+	s.Assortativity = assortativity(a, outd, ind)
+	u := NewUnionFind(len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			u.Union(NI(fr), to)
+		}
+	}
+	s.Components = u.Count()
+	for n := range a {
+		if z := u.Size(NI(n)); z > s.LargestComponent {
+			s.LargestComponent = z
+		}
+	}
+	scc := make([]int, len(a))
+	nc, big := 0, 0
+	g.StronglyConnectedComponents(func(c []NI) bool {
+		nc++
+		for _, n := range c {
+			scc[n] = nc
+		}
+		if len(c) > s.LargestStrongComponent {
+			s.LargestStrongComponent = len(c)
+			big = nc
+		}
+		return true
+	})
+	t, _ := g.Transpose()
+	s.Radius, s.Diameter, s.Samples =
+		eccentricityRange(a, t.AdjacencyList, scc, big, samples, rr)
+	return s
+}
+
+// String formats statistics as a text report, one statistic per line,
+// followed by a table of the degree distribution.
+func (s Statistics) String() string {
+	var b bytes.Buffer
+	line := func(name string, v interface{}) {
+		fmt.Fprintf(&b, "%-24s %v\n", name, v)
+	}
+	if s.Directed {
+		b.WriteString("Directed graph\n")
+	} else {
+		b.WriteString("Undirected graph\n")
+	}
+	line("Order", s.Order)
+	if s.Directed {
+		line("Arcs", s.Size)
+	} else {
+		line("Edges", s.Size)
+	}
+	line("Density", fmt.Sprintf("%.4g", s.Density))
+	deg := func(name string, d DegreeStats) {
+		line(name+" min/max", fmt.Sprint(d.Min, " / ", d.Max))
+		line(name+" mean/variance",
+			fmt.Sprintf("%.4g / %.4g", d.Mean, d.Variance))
+	}
+	if s.Directed {
+		deg("Out-degree", s.Degree)
+		deg("In-degree", s.InDegree)
+	} else {
+		deg("Degree", s.Degree)
+	}
+	line("Assortativity", fmt.Sprintf("%.4g", s.Assortativity))
+	if s.Directed {
+		line("Weak components", s.Components)
+		line("Largest weak component", s.LargestComponent)
+		line("Largest strong comp.", s.LargestStrongComponent)
+	} else {
+		line("Components", s.Components)
+		line("Largest component", s.LargestComponent)
+	}
+	if s.Samples > 0 {
+		line("Diameter (sampled)", fmt.Sprint(">= ", s.Diameter))
+		line("Radius (sampled)", fmt.Sprint("<= ", s.Radius))
+		line("Samples", s.Samples)
+	} else {
+		line("Diameter", s.Diameter)
+		line("Radius", s.Radius)
+	}
+	dist := func(name string, d []int) {
+		fmt.Fprintf(&b, "%-8s %8s\n", name, "Nodes")
+		for k, c := range d {
+			if c > 0 {
+				fmt.Fprintf(&b, "%-8d %8d\n", k, c)
+			}
+		}
+	}
+	if s.Directed {
+		dist("Out-deg", s.Degree.Distribution)
+		dist("In-deg", s.InDegree.Distribution)
+	} else {
+		dist("Degree", s.Degree.Distribution)
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Statistics() {
+	// 0--1--2--3   5--6
+	//    |  |
+	//    4--+
+	var g graph.Undirected
+	for _, e := range []graph.Edge{
+		{0, 1}, {1, 2}, {2, 3}, {1, 4}, {2, 4}, {5, 6},
+	} {
+		g.AddEdge(e.N1, e.N2)
+	}
+	fmt.Print(g.Statistics(0, nil))
+	// Output:
+	// Undirected graph
+	// Order                    7
+	// Edges                    6
+	// Density                  0.2857
+	// Degree min/max           1 / 3
+	// Degree mean/variance     1.714 / 0.7755
+	// Assortativity            -0.03448
+	// Components               2
+	// Largest component        5
+	// Diameter                 3
+	// Radius                   2
+	// Degree      Nodes
+	// 1               4
+	// 2               1
+	// 3               2
+}
+
+func ExampleDirected_Statistics() {
+	// 0->1->2->0, 2->3, 4->3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {0, 3},
+		3: {},
+		4: {3},
+	}}
+	s := g.Statistics(0, nil)
+	fmt.Println("Arcs:", s.Size)
+	fmt.Println("Out-degree distribution:", s.Degree.Distribution)
+	fmt.Println("In-degree distribution: ", s.InDegree.Distribution)
+	fmt.Println("Weak components:", s.Components)
+	fmt.Println("Largest strong component:", s.LargestStrongComponent)
+	fmt.Println("Diameter:", s.Diameter)
+	fmt.Println("Radius:", s.Radius)
+	// Output:
+	// Arcs: 5
+	// Out-degree distribution: [1 3 1]
+	// In-degree distribution:  [1 3 1]
+	// Weak components: 1
+	// Largest strong component: 3
+	// Diameter: 2
+	// Radius: 2
+}
+
+func TestStatisticsSampled(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for tc := 0; tc < 20; tc++ {
+		g := graph.GnmUndirected(50, 60, r)
+		exact := g.Statistics(0, nil)
+		if exact.Samples != 0 {
+			t.Fatal("exact statistics have samples")
+		}
+		s := g.Statistics(5, r)
+		if s.Samples != 5 && s.Samples != 0 {
+			t.Fatal("samples:", s.Samples)
+		}
+		if s.Diameter > exact.Diameter || s.Radius < exact.Radius {
+			t.Fatalf("sampled diameter %d radius %d, exact %d %d",
+				s.Diameter, s.Radius, exact.Diameter, exact.Radius)
+		}
+		if s.Components != exact.Components ||
+			s.LargestComponent != exact.LargestComponent {
+			t.Fatal("components differ")
+		}
+		// directed graph with all arcs reciprocal gives the same result
+		d := graph.Directed{g.AdjacencyList}.Statistics(0, nil)
+		if d.Diameter != exact.Diameter || d.Radius != exact.Radius ||
+			d.Components != exact.Components ||
+			d.LargestStrongComponent != exact.LargestComponent {
+			t.Fatal("directed statistics differ")
+		}
+		if !(math.IsNaN(d.Assortativity) && math.IsNaN(exact.Assortativity)) &&
+			math.Abs(d.Assortativity-exact.Assortativity) > 1e-9 {
+			t.Fatal("assortativity:", d.Assortativity, exact.Assortativity)
+		}
+	}
+}
+
+func TestStatisticsExact(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	for tc := 0; tc < 50; tc++ {
+		// connected graph, a random tree plus random edges
+		n := 1 + r.Intn(40)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for v := 1; v < n; v++ {
+			g.AddEdge(graph.NI(r.Intn(v)), graph.NI(v))
+		}
+		for i := r.Intn(n); i > 0; i-- {
+			u, v := r.Intn(n), r.Intn(n)
+			if u != v {
+				g.AddEdge(graph.NI(u), graph.NI(v))
+			}
+		}
+		dMax, rMin := 0, n
+		dist := make([]int, n)
+		for start := range g.AdjacencyList {
+			for v := range dist {
+				dist[v] = -1
+			}
+			dist[start] = 0
+			e := 0
+			q := []graph.NI{graph.NI(start)}
+			for len(q) > 0 {
+				u := q[0]
+				q = q[1:]
+				for _, v := range g.AdjacencyList[u] {
+					if dist[v] < 0 {
+						dist[v] = dist[u] + 1
+						e = dist[v]
+						q = append(q, v)
+					}
+				}
+			}
+			if e > dMax {
+				dMax = e
+			}
+			if e < rMin {
+				rMin = e
+			}
+		}
+		s := g.Statistics(0, nil)
+		if s.Diameter != dMax || s.Radius != rMin {
+			t.Fatalf("diameter %d radius %d, want %d %d",
+				s.Diameter, s.Radius, dMax, rMin)
+		}
+		d := graph.Directed{g.AdjacencyList}.Statistics(0, nil)
+		if d.Diameter != dMax || d.Radius != rMin {
+			t.Fatalf("directed diameter %d radius %d, want %d %d",
+				d.Diameter, d.Radius, dMax, rMin)
+		}
+	}
+}
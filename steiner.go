@@ -0,0 +1,196 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/soniakeys/bits"
+)
+
+// steiner.go has approximation algorithms for the Steiner tree problem,
+// finding a minimum weight tree in an undirected graph connecting a given
+// set of terminal nodes.  The problem is NP-hard.  Both algorithms here
+// find trees with weight at most 2(1 - 1/k) times optimal where k is the
+// number of terminals.
+
+// SteinerKMB approximates a minimum Steiner tree by the algorithm of Kou,
+// Markowsky, and Berman.
+//
+// A Steiner tree is a tree of g connecting all nodes of terminals.
+// Other nodes of g may be included in the tree as needed.  WeightFunc w
+// must return non-negative weights.
+//
+// The algorithm constructs the metric closure of g restricted to terminals,
+// the complete graph on terminals where edge weights are shortest path
+// distances in g.  A minimum spanning tree of the closure is found and its
+// edges are replaced by shortest paths in g.  A minimum spanning tree of
+// that subgraph is found and non-terminal leaves are pruned.  Time is
+// dominated by computing shortest paths from each terminal, O(k m log n)
+// for k terminals.
+//
+// The tree is returned as an undirected graph with the same order as g.
+// Also returned is the total weight of the tree.  If terminals are in
+// different connected components of g, a Steiner tree is returned for the
+// terminals of each component.
+//
+// See also SteinerMehlhorn, which is faster and gives the same bound on the
+// approximation.
+func (g LabeledUndirected) SteinerKMB(terminals []NI, w WeightFunc) (tree LabeledUndirected, dist float64) {
+	a := g.LabeledAdjacencyList
+	k := len(terminals)
+	// metric closure, with shortest path trees from each terminal
+	type path struct {
+		f      FromList
+		labels []LI
+	}
+	paths := make([]path, k)
+	closure := WeightedEdgeList{Order: k}
+	var cw []float64 // closure weights, indexed by closure edge label
+	for i, t := range terminals {
+		f, labels, d, _ := a.Dijkstra(t, -1, w)
+		paths[i] = path{f, labels}
+		for j := i + 1; j < k; j++ {
+			if f.Paths[terminals[j]].Len > 0 {
+				closure.Edges = append(closure.Edges,
+					LabeledEdge{Edge{NI(i), NI(j)}, LI(len(cw))})
+				cw = append(cw, d[terminals[j]])
+			}
+		}
+	}
+	closure.WeightFunc = func(l LI) float64 { return cw[l] }
+	ct, _ := closure.Kruskal()
+	// replace closure edges with paths
+	var sub []LabeledEdge
+	ct.Edges(func(e LabeledEdge) {
+		p := paths[e.N1]
+		for n := terminals[e.N2]; n != terminals[e.N1]; {
+			fr := p.f.Paths[n].From
+			sub = append(sub, LabeledEdge{Edge{fr, n}, p.labels[n]})
+			n = fr
+		}
+	})
+	return steinerPrune(len(a), sub, terminals, w)
+}
+
+// steinerPrune finds a minimum spanning forest of the edges sub and removes
+// non-terminal leaves.
+func steinerPrune(order int, sub []LabeledEdge, terminals []NI, w WeightFunc) (tree LabeledUndirected, dist float64) {
+	tree, dist = WeightedEdgeList{order, w, sub}.Kruskal()
+	a := tree.LabeledAdjacencyList
+	term := bits.New(order)
+	for _, t := range terminals {
+		term.SetBit(int(t), 1)
+	}
+	var leaves []NI
+	for n, to := range a {
+		if len(to) == 1 && term.Bit(n) == 0 {
+			leaves = append(leaves, NI(n))
+		}
+	}
+	for len(leaves) > 0 {
+		last := len(leaves) - 1
+		n := leaves[last]
+		leaves = leaves[:last]
+		h := a[n][0]
+		tree.RemoveEdgeLabel(n, h.To, h.Label)
+		dist -= w(h.Label)
+		if len(a[h.To]) == 1 && term.Bit(int(h.To)) == 0 {
+			leaves = append(leaves, h.To)
+		}
+	}
+	return
+}
+
+// SteinerMehlhorn approximates a minimum Steiner tree by the algorithm of
+// Mehlhorn.
+//
+// A Steiner tree is a tree of g connecting all nodes of terminals.
+// Other nodes of g may be included in the tree as needed.  WeightFunc w
+// must return non-negative weights.
+//
+// Rather than the full metric closure of SteinerKMB, the algorithm uses a
+// single multi-source shortest path search to partition nodes by their
+// nearest terminal.  Edges between partitions give a graph on terminals
+// with the same minimum spanning trees as the metric closure.  Time is
+// O(m log n).
+//
+// The tree is returned as an undirected graph with the same order as g.
+// Also returned is the total weight of the tree.  If terminals are in
+// different connected components of g, a Steiner tree is returned for the
+// terminals of each component.
+func (g LabeledUndirected) SteinerMehlhorn(terminals []NI, w WeightFunc) (tree LabeledUndirected, dist float64) {
+	a := g.LabeledAdjacencyList
+	// multi-source Dijkstra, finding the nearest terminal of each node
+	base := make([]NI, len(a))
+	from := make([]Half, len(a))
+	r := make([]tentResult, len(a))
+	for n := range r {
+		r[n] = tentResult{dist: math.Inf(1), nx: NI(n), fx: -1}
+		base[n] = -1
+	}
+	var t tent
+	for _, s := range terminals {
+		if base[s] < 0 {
+			base[s] = s
+			from[s] = Half{-1, 0}
+			r[s].dist = 0
+			heap.Push(&t, &r[s])
+		}
+	}
+	for len(t) > 0 {
+		cr := heap.Pop(&t).(*tentResult)
+		cr.done = true
+		n := cr.nx
+		for _, h := range a[n] {
+			hr := &r[h.To]
+			if hr.done {
+				continue
+			}
+			if d := cr.dist + w(h.Label); d < hr.dist {
+				hr.dist = d
+				base[h.To] = base[n]
+				from[h.To] = Half{n, h.Label}
+				if hr.fx < 0 {
+					heap.Push(&t, hr)
+				} else {
+					heap.Fix(&t, hr.fx)
+				}
+			}
+		}
+	}
+	// bridging edges between partitions, in order of path distance
+	type bridge struct {
+		e LabeledEdge
+		d float64
+	}
+	var bridges []bridge
+	for n1, to := range a {
+		for _, h := range to {
+			n2 := h.To
+			if NI(n1) < n2 && base[n1] >= 0 && base[n1] != base[n2] {
+				bridges = append(bridges, bridge{LabeledEdge{Edge{NI(n1), n2}, h.Label},
+					r[n1].dist + w(h.Label) + r[n2].dist})
+			}
+		}
+	}
+	sort.Slice(bridges, func(i, j int) bool { return bridges[i].d < bridges[j].d })
+	// Kruskal on terminals, expanding bridges to paths
+	ds := NewUnionFind(len(a))
+	var sub []LabeledEdge
+	for _, b := range bridges {
+		if !ds.Union(base[b.e.N1], base[b.e.N2]) {
+			continue
+		}
+		sub = append(sub, b.e)
+		for _, n := range []NI{b.e.N1, b.e.N2} {
+			for ; from[n].To >= 0; n = from[n].To {
+				sub = append(sub, LabeledEdge{Edge{from[n].To, n}, from[n].Label})
+			}
+		}
+	}
+	return steinerPrune(len(a), sub, terminals, w)
+}
@@ -0,0 +1,167 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+// steinerExample returns a graph where node 3, not a terminal, is needed
+// for a minimum Steiner tree connecting terminals 0, 1, and 2.
+func steinerExample() graph.LabeledUndirected {
+	//         0
+	//        /|\
+	//    (5)/ | \(5)
+	//      /  |(2)
+	//     /   3   \
+	//    / (2/ \(2)\
+	//   /   /   \   \
+	//  1-----------2
+	//       (5)
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 5)
+	g.AddEdge(graph.Edge{0, 2}, 5)
+	g.AddEdge(graph.Edge{1, 2}, 5)
+	g.AddEdge(graph.Edge{0, 3}, 2)
+	g.AddEdge(graph.Edge{1, 3}, 2)
+	g.AddEdge(graph.Edge{2, 3}, 2)
+	return g
+}
+
+func ExampleLabeledUndirected_SteinerKMB() {
+	g := steinerExample()
+	w := func(l graph.LI) float64 { return float64(l) }
+	t, dist := g.SteinerKMB([]graph.NI{0, 1, 2}, w)
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total weight:", dist)
+	// Output:
+	// 0 [{3 2}]
+	// 1 [{3 2}]
+	// 2 [{3 2}]
+	// 3 [{0 2} {1 2} {2 2}]
+	// total weight: 6
+}
+
+func ExampleLabeledUndirected_SteinerMehlhorn() {
+	g := steinerExample()
+	w := func(l graph.LI) float64 { return float64(l) }
+	t, dist := g.SteinerMehlhorn([]graph.NI{0, 1, 2}, w)
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total weight:", dist)
+	// Output:
+	// 0 [{3 2}]
+	// 1 [{3 2}]
+	// 2 [{3 2}]
+	// 3 [{1 2} {0 2} {2 2}]
+	// total weight: 6
+}
+
+func TestSteiner(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 100; i++ {
+		n := 2 + r.Intn(8)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for m := n + r.Intn(2*n); m > 0; m-- {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(1+r.Intn(9)))
+		}
+		perm := r.Perm(n)
+		terminals := make([]graph.NI, 1+r.Intn(n))
+		for j := range terminals {
+			terminals[j] = graph.NI(perm[j])
+		}
+		opt := steinerBF(g, terminals, w)
+		bound := 2 * (1 - 1/float64(len(terminals))) * opt
+		for _, alg := range []struct {
+			name string
+			f    func([]graph.NI, graph.WeightFunc) (graph.LabeledUndirected, float64)
+		}{
+			{"KMB", g.SteinerKMB},
+			{"Mehlhorn", g.SteinerMehlhorn},
+		} {
+			tr, dist := alg.f(terminals, w)
+			sum := 0.
+			tr.Edges(func(e graph.LabeledEdge) { sum += w(e.LI) })
+			if sum != dist {
+				t.Fatal(alg.name, "dist", sum, dist)
+			}
+			if dist < opt || dist > bound+1e-9 {
+				t.Fatal(alg.name, "outside bound", dist, opt)
+			}
+			// terminals in the same component of g must be in the same
+			// component of the tree.
+			gc, _ := g.ConnectedComponentInts()
+			tc, _ := tr.ConnectedComponentInts()
+			for _, t1 := range terminals {
+				for _, t2 := range terminals {
+					if gc[t1] == gc[t2] && tc[t1] != tc[t2] {
+						t.Fatal(alg.name, "terminals not connected")
+					}
+				}
+			}
+			if ok, _ := tr.IsTree(terminals[0]); !ok {
+				t.Fatal(alg.name, "not a tree")
+			}
+		}
+	}
+}
+
+// steinerBF finds the weight of a minimum Steiner tree by finding minimum
+// spanning forests of g induced on terminals plus each subset of other nodes.
+// The graph induced must connect each component of terminals as well as g.
+func steinerBF(g graph.LabeledUndirected, terminals []graph.NI, w graph.WeightFunc) float64 {
+	a := g.LabeledAdjacencyList
+	n := len(a)
+	isTerm := make([]bool, n)
+	for _, t := range terminals {
+		isTerm[t] = true
+	}
+	var others []int
+	for i := 0; i < n; i++ {
+		if !isTerm[i] {
+			others = append(others, i)
+		}
+	}
+	gc, _ := g.ConnectedComponentInts()
+	best := math.Inf(1)
+	for s := 0; s < 1<<uint(len(others)); s++ {
+		in := append([]bool{}, isTerm...)
+		for j, o := range others {
+			if s&(1<<uint(j)) != 0 {
+				in[o] = true
+			}
+		}
+		l := graph.WeightedEdgeList{Order: n, WeightFunc: w}
+		g.Edges(func(e graph.LabeledEdge) {
+			if in[e.N1] && in[e.N2] {
+				l.Edges = append(l.Edges, e)
+			}
+		})
+		f, d := l.Kruskal()
+		fc, _ := f.ConnectedComponentInts()
+		ok := true
+		for _, t1 := range terminals {
+			for _, t2 := range terminals {
+				if gc[t1] == gc[t2] && fc[t1] != fc[t2] {
+					ok = false
+				}
+			}
+		}
+		if ok && d < best {
+			best = d
+		}
+	}
+	return best
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"errors"
+	"sort"
+)
+
+// treduce.go has transitive reduction and minimum equivalent graphs.
+//
+// Both remove arcs of a directed graph while preserving reachability
+// between all pairs of nodes.  Results take memory linear in the size of
+// the graph, unlike TransitiveClosure.
+
+// TransitiveReduction computes the transitive reduction of a directed
+// acyclic graph.
+//
+// The transitive reduction is the unique subgraph with the fewest arcs
+// having the same reachability as g.  An arc is removed if its end node is
+// reachable from its start node by another path.  Parallel arcs are
+// reduced to a single arc.
+//
+// Returned is the reduced graph, a new Directed with arcs in the same order
+// as in g, and the list of removed arcs, as Edges with N1 the start node
+// and N2 the end node of each arc.  If g is cyclic, an error is returned.
+//
+// For each node, a search is done from each of its out-neighbors in
+// topological order, so time is O(nm) in the worst case but typically much
+// less for sparse graphs.  Memory is O(n+m).
+//
+// See also MinimumEquivalentGraph for graphs that may be cyclic.
+func (g Directed) TransitiveReduction() (r Directed, removed []Edge, err error) {
+	ordering, cycle := g.Topological()
+	if cycle != nil {
+		return Directed{}, nil, errors.New("graph is cyclic")
+	}
+	r.AdjacencyList, removed = transitiveReduce(g.AdjacencyList, ordering)
+	return r, removed, nil
+}
+
+// transitiveReduce reduces DAG a, given a topological ordering.
+func transitiveReduce(a AdjacencyList, ordering []NI) (r AdjacencyList, removed []Edge) {
+	pos := make([]int, len(a))
+	for p, n := range ordering {
+		pos[n] = p
+	}
+	mark := make([]int, len(a)) // u+1 if reached from a kept out-neighbor of u
+	keep := make([]bool, len(a))
+	var to2, stack []NI
+	r = make(AdjacencyList, len(a))
+	for u, to := range a {
+		// an out-neighbor v can only be reached through out-neighbors
+		// earlier in topological order, so visit them in that order.
+		to2 = append(to2[:0], to...)
+		sort.Slice(to2, func(i, j int) bool { return pos[to2[i]] < pos[to2[j]] })
+		for _, v := range to2 {
+			if mark[v] == u+1 {
+				continue
+			}
+			keep[v] = true
+			mark[v] = u + 1
+			stack = append(stack[:0], v)
+			for len(stack) > 0 {
+				n := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, w := range a[n] {
+					if mark[w] != u+1 {
+						mark[w] = u + 1
+						stack = append(stack, w)
+					}
+				}
+			}
+		}
+		var rt []NI
+		for _, v := range to {
+			if keep[v] {
+				rt = append(rt, v)
+				keep[v] = false // keep only the first of parallel arcs
+			} else {
+				removed = append(removed, Edge{NI(u), v})
+			}
+		}
+		r[u] = rt
+	}
+	return
+}
+
+// MinimumEquivalentGraph computes a subgraph of a directed graph with the
+// same reachability and few arcs.
+//
+// The graph is condensed by Condensation and arcs between components are
+// reduced as by TransitiveReduction, keeping a single arc of g for each arc
+// of the reduced condensation.  Finding a minimum equivalent graph within
+// a strongly connected component is NP-hard.  Within each component, arcs
+// are kept for a breadth-first out-tree and in-tree from a node of the
+// component, at most 2(k-1) arcs for a component of k nodes, at most twice
+// the minimum.  Loops are removed.  For a directed acyclic graph, the result
+// is the transitive reduction and is minimum.
+//
+// Returned is the reduced graph, a new Directed with arcs in the same order
+// as in g, and the list of removed arcs, as Edges with N1 the start node
+// and N2 the end node of each arc.
+func (g Directed) MinimumEquivalentGraph() (r Directed, removed []Edge) {
+	a := g.AdjacencyList
+	scc, cd := g.Condensation()
+	comp := make([]NI, len(a))
+	for c, ns := range scc {
+		for _, n := range ns {
+			comp[n] = NI(c)
+		}
+	}
+	// components are in reverse topological order
+	ordering := make([]NI, len(cd))
+	for c := range cd {
+		ordering[c] = NI(len(cd) - 1 - c)
+	}
+	rcd, _ := transitiveReduce(cd, ordering)
+	// kept[n] lists end nodes of arcs to keep from node n, within its own
+	// component for the in- and out-trees, across components for the
+	// reduced condensation.
+	kept := make([][]NI, len(a))
+	t, _ := g.Transpose()
+	parent := make([]NI, len(a))
+	for n := range parent {
+		parent[n] = -1
+	}
+	var frontier, next []NI
+	for c, ns := range scc {
+		if len(ns) == 1 {
+			continue
+		}
+		root := ns[0]
+		// BFS from root within component, calling keep for tree arcs
+		bfs := func(adj AdjacencyList, keep func(fr, to NI)) {
+			parent[root] = root
+			frontier = append(frontier[:0], root)
+			for len(frontier) > 0 {
+				next = next[:0]
+				for _, n := range frontier {
+					for _, to := range adj[n] {
+						if comp[to] == NI(c) && parent[to] < 0 {
+							parent[to] = n
+							keep(n, to)
+							next = append(next, to)
+						}
+					}
+				}
+				frontier, next = next, frontier
+			}
+			for _, n := range ns {
+				parent[n] = -1
+			}
+		}
+		// out-tree
+		bfs(a, func(fr, to NI) { kept[fr] = append(kept[fr], to) })
+		// in-tree, BFS through the transpose, so tree arc fr->to is arc
+		// to->fr of g.  an arc kept for both trees is listed twice.
+		bfs(t.AdjacencyList, func(fr, to NI) { kept[to] = append(kept[to], fr) })
+	}
+	// arcs across components, the first arc of g found for each arc of
+	// the reduced condensation.
+	mark := make([]int, len(cd)) // c1+1 for c2 of wanted arcs c1->c2
+	for c1, ns := range scc {
+		for _, c2 := range rcd[c1] {
+			mark[c2] = c1 + 1
+		}
+		for _, n := range ns {
+			for _, v := range a[n] {
+				if c2 := comp[v]; mark[c2] == c1+1 {
+					mark[c2] = 0
+					kept[n] = append(kept[n], v)
+				}
+			}
+		}
+	}
+	// build r in order of g, keeping each kept arc once
+	r.AdjacencyList = make(AdjacencyList, len(a))
+	want := make([]bool, len(a)) // end nodes of arcs still to keep from n
+	for n, to := range a {
+		for _, v := range kept[n] {
+			want[v] = true
+		}
+		var rt []NI
+		for _, v := range to {
+			if want[v] {
+				want[v] = false
+				rt = append(rt, v)
+			} else {
+				removed = append(removed, Edge{NI(n), v})
+			}
+		}
+		r.AdjacencyList[n] = rt
+	}
+	return
+}
@@ -0,0 +1,141 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_TransitiveReduction() {
+	// 0-->1-->2-->3, with redundant arcs 0->2, 0->3, 1->3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2, 3},
+		1: {2, 3},
+		2: {3},
+		3: {},
+	}}
+	r, removed, err := g.TransitiveReduction()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range r.AdjacencyList {
+		fmt.Println(n, "->", to)
+	}
+	fmt.Println("removed:", removed)
+	// Output:
+	// 0 -> [1]
+	// 1 -> [2]
+	// 2 -> [3]
+	// 3 -> []
+	// removed: [{0 2} {0 3} {1 3}]
+}
+
+func ExampleDirected_MinimumEquivalentGraph() {
+	// 0<=>1, 1->2, 0->2, 2->2
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {0, 2},
+		2: {2},
+	}}
+	r, removed := g.MinimumEquivalentGraph()
+	for n, to := range r.AdjacencyList {
+		fmt.Println(n, "->", to)
+	}
+	fmt.Println("removed:", removed)
+	// Output:
+	// 0 -> [1]
+	// 1 -> [0 2]
+	// 2 -> []
+	// removed: [{0 2} {2 2}]
+}
+
+func sameClosure(g, r graph.Directed) bool {
+	cg := g.TransitiveClosure()
+	cr := r.TransitiveClosure()
+	for n := range cg {
+		if !cg[n].Equal(cr[n]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for tc := 0; tc < 100; tc++ {
+		n := 1 + r.Intn(20)
+		// random DAG with parallel arcs
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for i := r.Intn(3 * n); i > 0; i-- {
+			u, v := r.Intn(n), r.Intn(n)
+			if u < v {
+				g.AdjacencyList[u] = append(g.AdjacencyList[u], graph.NI(v))
+			}
+		}
+		red, removed, err := g.TransitiveReduction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if red.ArcSize()+len(removed) != g.ArcSize() {
+			t.Fatal("arcs not accounted for")
+		}
+		if !sameClosure(g, red) {
+			t.Fatal("closure differs")
+		}
+		// every remaining arc is needed
+		for u, to := range red.AdjacencyList {
+			for x := range to {
+				c, _ := red.AdjacencyList.Copy()
+				c[u] = append(c[u][:x:x], c[u][x+1:]...)
+				if sameClosure(red, graph.Directed{c}) {
+					t.Fatal("arc", u, to[x], "not needed")
+				}
+			}
+		}
+		// a DAG gives the same result with MinimumEquivalentGraph
+		meg, _ := g.MinimumEquivalentGraph()
+		if meg.ArcSize() != red.ArcSize() || !sameClosure(g, meg) {
+			t.Fatal("MinimumEquivalentGraph differs on DAG")
+		}
+	}
+	g := graph.Directed{graph.AdjacencyList{0: {1}, 1: {0}}}
+	if _, _, err := g.TransitiveReduction(); err == nil {
+		t.Fatal("no error for cyclic graph")
+	}
+}
+
+func TestMinimumEquivalentGraph(t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	for tc := 0; tc < 100; tc++ {
+		n := 1 + r.Intn(20)
+		g := graph.GnmDirected(n, r.Intn(3*n), r)
+		meg, removed := g.MinimumEquivalentGraph()
+		if meg.ArcSize()+len(removed) != g.ArcSize() {
+			t.Fatal("arcs not accounted for")
+		}
+		if !sameClosure(g, meg) {
+			t.Fatal("closure differs")
+		}
+		for u, to := range meg.AdjacencyList {
+			for _, v := range to {
+				if has, _ := g.HasArc(graph.NI(u), v); !has {
+					t.Fatal("arc not in g")
+				}
+			}
+		}
+		scc, cd := g.Condensation()
+		within := 0
+		for _, c := range scc {
+			within += 2 * (len(c) - 1)
+		}
+		if meg.ArcSize() > within+cd.ArcSize() {
+			t.Fatal("too many arcs")
+		}
+	}
+}
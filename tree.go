@@ -0,0 +1,436 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "github.com/soniakeys/bits"
+
+// tree.go has FromList methods computing structural properties of trees:
+// subtree sizes, heights, diameter, center, and centroids.
+
+// bottomUp returns an ordering of the nodes of f where each node follows
+// all of its children.  f must be acyclic.
+func (f FromList) bottomUp() []NI {
+	p := f.Paths
+	nc := make([]int, len(p)) // number of children not yet ordered
+	for _, e := range p {
+		if e.From >= 0 {
+			nc[e.From]++
+		}
+	}
+	o := make([]NI, 0, len(p))
+	for n, c := range nc {
+		if c == 0 {
+			o = append(o, NI(n)) // leaves first
+		}
+	}
+	for i := 0; i < len(o); i++ {
+		if fr := p[o[i]].From; fr >= 0 {
+			if nc[fr]--; nc[fr] == 0 {
+				o = append(o, fr)
+			}
+		}
+	}
+	return o
+}
+
+// treeTraverse traverses the tree of undirected forest a containing start.
+//
+// Returned are the nodes of the tree in breadth first order, the from node
+// of each node in the traversal, and the distance of each node from start.
+// Only values for nodes of order are meaningful in from and dist.
+func treeTraverse(a AdjacencyList, start NI) (order, from []NI, dist []int) {
+	from = make([]NI, len(a))
+	dist = make([]int, len(a))
+	from[start] = -1
+	order = []NI{start}
+	for i := 0; i < len(order); i++ {
+		n := order[i]
+		for _, to := range a[n] {
+			if to != from[n] {
+				from[to] = n
+				dist[to] = dist[n] + 1
+				order = append(order, to)
+			}
+		}
+	}
+	return
+}
+
+// treeTraverseLabeled is the weighted version of treeTraverse.
+func treeTraverseLabeled(a LabeledAdjacencyList, start NI, w WeightFunc) (order, from []NI, dist []float64) {
+	from = make([]NI, len(a))
+	dist = make([]float64, len(a))
+	from[start] = -1
+	order = []NI{start}
+	for i := 0; i < len(order); i++ {
+		n := order[i]
+		for _, to := range a[n] {
+			if to.To != from[n] {
+				from[to.To] = n
+				dist[to.To] = dist[n] + w(to.Label)
+				order = append(order, to.To)
+			}
+		}
+	}
+	return
+}
+
+// SubtreeSizes returns the number of nodes in the subtree rooted at each
+// node of f.
+//
+// The subtree size of a leaf is 1.  The subtree size of a root is the number
+// of nodes in its tree.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+func (f FromList) SubtreeSizes() []int {
+	p := f.Paths
+	s := make([]int, len(p))
+	for _, n := range f.bottomUp() {
+		s[n]++
+		if fr := p[n].From; fr >= 0 {
+			s[fr] += s[n]
+		}
+	}
+	return s
+}
+
+// Heights returns the height of the subtree rooted at each node of f.
+//
+// The height of a node is the number of arcs on the longest path from the
+// node down to a leaf.  The height of a leaf is 0.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+//
+// See also HeightsLabeled.
+func (f FromList) Heights() []int {
+	p := f.Paths
+	h := make([]int, len(p))
+	for _, n := range f.bottomUp() {
+		if fr := p[n].From; fr >= 0 && h[n]+1 > h[fr] {
+			h[fr] = h[n] + 1
+		}
+	}
+	return h
+}
+
+// HeightsLabeled returns the weighted height of the subtree rooted at each
+// node of f.
+//
+// The weighted height of a node is the maximum distance from the node down
+// to a leaf.  Argument labels gives the label of the arc leading to each node,
+// as returned for example by LabeledUndirected.Prim.  WeightFunc w gives
+// arc weights, which should be non-negative.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+//
+// See also Heights.
+func (f FromList) HeightsLabeled(labels []LI, w WeightFunc) []float64 {
+	p := f.Paths
+	h := make([]float64, len(p))
+	for _, n := range f.bottomUp() {
+		if fr := p[n].From; fr >= 0 {
+			if d := h[n] + w(labels[n]); d > h[fr] {
+				h[fr] = d
+			}
+		}
+	}
+	return h
+}
+
+// Diameter returns the diameter of a tree and end nodes of a path realizing
+// the diameter.
+//
+// The diameter of a tree is the number of arcs on a longest path between any
+// two nodes, regardless of arc direction.  If f represents a forest, the
+// result is the largest diameter of any tree in the forest.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+// If f is empty, Diameter returns 0, -1, -1.
+//
+// See also DiameterLabeled.
+func (f FromList) Diameter() (d int, n1, n2 NI) {
+	p := f.Paths
+	if len(p) == 0 {
+		return 0, -1, -1
+	}
+	h := make([]int, len(p))   // height
+	deep := make([]NI, len(p)) // a deepest node in subtree
+	for n := range deep {
+		deep[n] = NI(n)
+	}
+	n1, n2 = 0, 0
+	for _, n := range f.bottomUp() {
+		fr := p[n].From
+		if fr < 0 {
+			continue
+		}
+		hn := h[n] + 1
+		if c := h[fr] + hn; c > d {
+			d, n1, n2 = c, deep[fr], deep[n]
+		}
+		if hn > h[fr] {
+			h[fr] = hn
+			deep[fr] = deep[n]
+		}
+	}
+	return
+}
+
+// DiameterLabeled returns the weighted diameter of a tree and end nodes of
+// a path realizing the diameter.
+//
+// The weighted diameter of a tree is the maximum distance between any two
+// nodes, regardless of arc direction.  If f represents a forest, the result
+// is the largest diameter of any tree in the forest.
+//
+// Argument labels gives the label of the arc leading to each node, as
+// returned for example by LabeledUndirected.Prim.  WeightFunc w gives
+// arc weights, which should be non-negative.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+// If f is empty, DiameterLabeled returns 0, -1, -1.
+//
+// See also Diameter.
+func (f FromList) DiameterLabeled(labels []LI, w WeightFunc) (d float64, n1, n2 NI) {
+	p := f.Paths
+	if len(p) == 0 {
+		return 0, -1, -1
+	}
+	h := make([]float64, len(p))
+	deep := make([]NI, len(p))
+	for n := range deep {
+		deep[n] = NI(n)
+	}
+	n1, n2 = 0, 0
+	for _, n := range f.bottomUp() {
+		fr := p[n].From
+		if fr < 0 {
+			continue
+		}
+		hn := h[n] + w(labels[n])
+		if c := h[fr] + hn; c > d {
+			d, n1, n2 = c, deep[fr], deep[n]
+		}
+		if hn > h[fr] {
+			h[fr] = hn
+			deep[fr] = deep[n]
+		}
+	}
+	return
+}
+
+// Center returns the center of the tree containing node n.
+//
+// The center is the set of nodes of minimum eccentricity, where eccentricity
+// is the maximum number of arcs to any other node of the tree, regardless
+// of arc direction.  A tree has either one or two center nodes.  If it has
+// one, it is returned as c1 and c2 is returned as -1.  If it has two, they
+// are adjacent.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+//
+// See also CenterLabeled.
+func (f FromList) Center(n NI) (c1, c2 NI) {
+	u, _ := f.Undirected(nil)
+	a := u.AdjacencyList
+	// end x of a diameter path is a farthest node from any node
+	order, _, dist := treeTraverse(a, n)
+	x := n
+	for _, m := range order {
+		if dist[m] > dist[x] {
+			x = m
+		}
+	}
+	order, from, dist := treeTraverse(a, x)
+	y := x
+	for _, m := range order {
+		if dist[m] > dist[y] {
+			y = m
+		}
+	}
+	// walk half way back along the diameter path from y to x
+	l := dist[y]
+	c1 = y
+	for i := 0; i < l/2; i++ {
+		c1 = from[c1]
+	}
+	if l%2 == 0 {
+		return c1, -1
+	}
+	return c1, from[c1]
+}
+
+// CenterLabeled returns the weighted center of the tree containing node n.
+//
+// The weighted center is the node of minimum weighted eccentricity, where
+// weighted eccentricity is the maximum distance to any other node of the
+// tree, regardless of arc direction.  If two nodes have the minimum
+// eccentricity, both are returned, otherwise c2 is returned as -1.
+//
+// Argument labels gives the label of the arc leading to each node, as
+// returned for example by LabeledUndirected.Prim.  WeightFunc w gives
+// arc weights, which should be non-negative.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+//
+// See also Center.
+func (f FromList) CenterLabeled(n NI, labels []LI, w WeightFunc) (c1, c2 NI) {
+	u, _ := f.LabeledUndirected(labels, nil)
+	a := u.LabeledAdjacencyList
+	order, _, dist := treeTraverseLabeled(a, n, w)
+	x := n
+	for _, m := range order {
+		if dist[m] > dist[x] {
+			x = m
+		}
+	}
+	order, from, dist := treeTraverseLabeled(a, x, w)
+	y := x
+	for _, m := range order {
+		if dist[m] > dist[y] {
+			y = m
+		}
+	}
+	// a node of minimum eccentricity lies on the diameter path.
+	// its eccentricity is the larger of its distances to the two ends.
+	c1, c2 = y, -1
+	best := dist[y]
+	for m := y; m >= 0; m = from[m] {
+		e := dist[m]
+		if r := dist[y] - dist[m]; r > e {
+			e = r
+		}
+		switch {
+		case e < best:
+			c1, c2, best = m, -1, e
+		case e == best && m != c1:
+			c2 = m
+		}
+	}
+	return
+}
+
+// Centroid returns the centroid of the tree containing node n.
+//
+// A centroid is a node whose removal leaves no connected component with
+// more than half of the nodes of the tree.  A tree has either one or two
+// centroids.  If it has one, it is returned as c1 and c2 is returned as -1.
+// If it has two, they are adjacent.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+//
+// See also CentroidDecomposition.
+func (f FromList) Centroid(n NI) (c1, c2 NI) {
+	u, _ := f.Undirected(nil)
+	order, from, _ := treeTraverse(u.AdjacencyList, n)
+	size := make([]int, len(f.Paths))
+	maxPart := make([]int, len(f.Paths)) // largest child subtree
+	for i := len(order) - 1; i >= 0; i-- {
+		m := order[i]
+		size[m]++
+		if fr := from[m]; fr >= 0 {
+			size[fr] += size[m]
+			if size[m] > maxPart[fr] {
+				maxPart[fr] = size[m]
+			}
+		}
+	}
+	total := len(order)
+	c1, c2 = -1, -1
+	for _, m := range order {
+		mp := maxPart[m]
+		if r := total - size[m]; r > mp {
+			mp = r
+		}
+		if mp*2 <= total {
+			if c1 < 0 {
+				c1 = m
+			} else {
+				c2 = m
+			}
+		}
+	}
+	return
+}
+
+// CentroidDecomposition computes the centroid decomposition of a tree or
+// forest.
+//
+// The centroid decomposition is a tree constructed by choosing a centroid
+// of a tree as root, removing it, and recursively decomposing each remaining
+// component, with centroids of the components becoming children of the root.
+// The height of the centroid decomposition is at most log2(n) where n
+// is the number of nodes of the largest tree.
+//
+// The result is returned as a new FromList, with a tree for each tree of f.
+// It is fully populated with From, Len, Leaves, and MaxLen.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+func (f FromList) CentroidDecomposition() FromList {
+	u, _ := f.Undirected(nil)
+	a := u.AdjacencyList
+	cd := NewFromList(len(a))
+	removed := bits.New(len(a))
+	size := make([]int, len(a))
+	from := make([]NI, len(a))
+	type comp struct {
+		n, parent NI // representative node, parent centroid
+		len       int
+	}
+	var stack []comp
+	for r, e := range f.Paths {
+		if e.From < 0 {
+			stack = append(stack, comp{NI(r), -1, 1})
+		}
+	}
+	var order []NI
+	for len(stack) > 0 {
+		last := len(stack) - 1
+		c := stack[last]
+		stack = stack[:last]
+		// traverse the component, computing subtree sizes
+		from[c.n] = -1
+		order = append(order[:0], c.n)
+		for i := 0; i < len(order); i++ {
+			n := order[i]
+			size[n] = 1
+			for _, to := range a[n] {
+				if to != from[n] && removed.Bit(int(to)) == 0 {
+					from[to] = n
+					order = append(order, to)
+				}
+			}
+		}
+		for i := len(order) - 1; i > 0; i-- {
+			n := order[i]
+			size[from[n]] += size[n]
+		}
+		// walk from c.n toward the centroid
+		half := len(order) / 2
+		n := c.n
+	walk:
+		for {
+			for _, to := range a[n] {
+				if to != from[n] && removed.Bit(int(to)) == 0 &&
+					size[to] > half {
+					n = to
+					continue walk
+				}
+			}
+			break
+		}
+		cd.Paths[n] = PathEnd{From: c.parent, Len: c.len}
+		if c.len > cd.MaxLen {
+			cd.MaxLen = c.len
+		}
+		removed.SetBit(int(n), 1)
+		for _, to := range a[n] {
+			if removed.Bit(int(to)) == 0 {
+				stack = append(stack, comp{to, n, c.len + 1})
+			}
+		}
+	}
+	cd.RecalcLeaves()
+	return cd
+}
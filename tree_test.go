@@ -0,0 +1,245 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleFromList_SubtreeSizes() {
+	//     0
+	//    / \
+	//   1   2
+	//  / \
+	// 3   4
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+	}}
+	fmt.Println(f.SubtreeSizes())
+	// Output:
+	// [5 3 1 1 1]
+}
+
+func ExampleFromList_Heights() {
+	//     0
+	//    / \
+	//   1   2
+	//  / \
+	// 3   4
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+	}}
+	fmt.Println(f.Heights())
+	// Output:
+	// [2 1 0 0 0]
+}
+
+func ExampleFromList_HeightsLabeled() {
+	//       0
+	//   (1)/ \(5)
+	//     1   2
+	// (2)/ \(1)
+	//   3   4
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+	}}
+	labels := []graph.LI{0, 1, 5, 2, 1}
+	w := func(l graph.LI) float64 { return float64(l) }
+	fmt.Println(f.HeightsLabeled(labels, w))
+	// Output:
+	// [5 2 0 0 0]
+}
+
+func ExampleFromList_Diameter() {
+	//     0
+	//    / \
+	//   1   2
+	//  / \
+	// 3   4
+	//      \
+	//       5
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+		5: {From: 4},
+	}}
+	fmt.Println(f.Diameter())
+	// Output:
+	// 4 2 5
+}
+
+func ExampleFromList_DiameterLabeled() {
+	//       0
+	//   (1)/ \(5)
+	//     1   2
+	// (2)/ \(1)
+	//   3   4
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+	}}
+	labels := []graph.LI{0, 1, 5, 2, 1}
+	w := func(l graph.LI) float64 { return float64(l) }
+	fmt.Println(f.DiameterLabeled(labels, w))
+	// Output:
+	// 8 2 3
+}
+
+func ExampleFromList_Center() {
+	//     0
+	//    / \
+	//   1   2
+	//  / \
+	// 3   4
+	//      \
+	//       5
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+		5: {From: 4},
+	}}
+	fmt.Println(f.Center(3))
+	// Output:
+	// 1 -1
+}
+
+func ExampleFromList_CenterLabeled() {
+	//       0
+	//   (1)/ \(5)
+	//     1   2
+	// (2)/ \(1)
+	//   3   4
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: 1},
+		4: {From: 1},
+	}}
+	labels := []graph.LI{0, 1, 5, 2, 1}
+	w := func(l graph.LI) float64 { return float64(l) }
+	fmt.Println(f.CenterLabeled(0, labels, w))
+	// Output:
+	// 0 -1
+}
+
+func ExampleFromList_Centroid() {
+	// 0--1--2--3
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 1},
+		3: {From: 2},
+	}}
+	fmt.Println(f.Centroid(0))
+	// Output:
+	// 1 2
+}
+
+func ExampleFromList_CentroidDecomposition() {
+	// 0--1--2--3--4--5--6
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 1},
+		3: {From: 2},
+		4: {From: 3},
+		5: {From: 4},
+		6: {From: 5},
+	}}
+	cd := f.CentroidDecomposition()
+	for n, e := range cd.Paths {
+		fmt.Println(n, e.From, e.Len)
+	}
+	fmt.Println("max len:", cd.MaxLen)
+	// Output:
+	// 0 1 3
+	// 1 3 2
+	// 2 1 3
+	// 3 -1 1
+	// 4 5 3
+	// 5 3 2
+	// 6 5 3
+	// max len: 3
+}
+
+func TestTreeMetrics(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 50; i++ {
+		f := randomForest(1+r.Intn(100), .03, r)
+		u, _ := f.Undirected(nil)
+		// brute force diameter, center, and eccentricities with BFS
+		ecc := make([]int, len(f.Paths))
+		diam := 0
+		for n := range u.AdjacencyList {
+			u.BreadthFirst(graph.NI(n), func(m graph.NI) {
+				if d := distBF(u, graph.NI(n), m); d > ecc[n] {
+					ecc[n] = d
+				}
+			})
+			if ecc[n] > diam {
+				diam = ecc[n]
+			}
+		}
+		d, n1, n2 := f.Diameter()
+		if d != diam || distBF(u, n1, n2) != d {
+			t.Fatal("Diameter", d, diam)
+		}
+		for n := range f.Paths {
+			c1, c2 := f.Center(graph.NI(n))
+			min := ecc[c1]
+			u.BreadthFirst(graph.NI(n), func(m graph.NI) {
+				if ecc[m] < min {
+					t.Fatal("Center", n, c1, c2)
+				}
+			})
+			if c2 >= 0 && ecc[c2] != min {
+				t.Fatal("Center c2", n, c1, c2)
+			}
+		}
+		cd := f.CentroidDecomposition()
+		if ok, _ := cd.Cyclic(); ok {
+			t.Fatal("cyclic centroid decomposition")
+		}
+		lim := 1
+		for 1<<uint(lim-1) <= len(f.Paths) {
+			lim++
+		}
+		if cd.MaxLen > lim {
+			t.Fatal("centroid decomposition too tall", cd.MaxLen, len(f.Paths))
+		}
+	}
+}
+
+// distBF returns the number of arcs on a shortest path from a to b.
+func distBF(g graph.Undirected, a, b graph.NI) int {
+	var f graph.FromList
+	g.SpanTree(a, &f)
+	return f.Paths[b].Len - 1
+}
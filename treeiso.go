@@ -0,0 +1,257 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// treeiso.go has canonical encoding and isomorphism of unlabeled trees.
+//
+// The encoding is AHU style, a balanced parenthesis string with a pair of
+// parentheses for each node enclosing the encodings of its children.  The
+// children are ordered canonically so that two trees have the same encoding
+// if and only if they are isomorphic.  Rather than sorting child encodings
+// as strings, nodes are ranked bottom up by height and then by the sorted
+// ranks of their children.  This gives O(n log n) time overall.
+
+// TreeCode is a canonical encoding of an unlabeled tree.
+//
+// Two trees have the same TreeCode if and only if they are isomorphic.
+// As a string type, a TreeCode can be used as a map key.
+type TreeCode string
+
+// Hash returns a 64 bit FNV-1a hash of c.
+//
+// Hash values are more compact than a TreeCode but unlike a TreeCode,
+// different trees can have the same hash value.
+func (c TreeCode) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(c))
+	return h.Sum64()
+}
+
+// canonTree computes the canonical encoding of the tree in a rooted at root.
+//
+// If undirected is false, a must represent an arborescence and the tree
+// encoded is the nodes reachable from root.  If undirected is true, a must
+// represent an undirected tree or forest and the tree encoded is the tree
+// containing root.
+//
+// Also returned are the children of each node of the tree in canonical
+// order.
+func canonTree(a AdjacencyList, root NI, undirected bool) (TreeCode, [][]NI) {
+	// breadth first traversal, collecting children
+	ch := make([][]NI, len(a))
+	from := make([]NI, len(a))
+	from[root] = -1
+	order := []NI{root}
+	for i := 0; i < len(order); i++ {
+		n := order[i]
+		for _, to := range a[n] {
+			if undirected && to == from[n] {
+				continue
+			}
+			from[to] = n
+			ch[n] = append(ch[n], to)
+			order = append(order, to)
+		}
+	}
+	// heights, bottom up
+	height := make([]int, len(a))
+	maxHeight := 0
+	for i := len(order) - 1; i > 0; i-- {
+		n := order[i]
+		if h := height[n] + 1; h > height[from[n]] {
+			height[from[n]] = h
+			if h > maxHeight {
+				maxHeight = h
+			}
+		}
+	}
+	byHeight := make([][]NI, maxHeight+1)
+	for _, n := range order {
+		h := height[n]
+		byHeight[h] = append(byHeight[h], n)
+	}
+	// rank nodes, height by height
+	rank := make([]int, len(a))
+	next := 0
+	for _, level := range byHeight {
+		for _, n := range level {
+			c := ch[n]
+			sort.Slice(c, func(i, j int) bool { return rank[c[i]] < rank[c[j]] })
+		}
+		less := func(x, y NI) bool {
+			cx, cy := ch[x], ch[y]
+			for i := 0; i < len(cx) && i < len(cy); i++ {
+				if rx, ry := rank[cx[i]], rank[cy[i]]; rx != ry {
+					return rx < ry
+				}
+			}
+			return len(cx) < len(cy)
+		}
+		sort.Slice(level, func(i, j int) bool { return less(level[i], level[j]) })
+		for i, n := range level {
+			if i > 0 && less(level[i-1], n) {
+				next++
+			}
+			rank[n] = next
+		}
+		next++
+	}
+	// emit parentheses in canonical order
+	code := make([]byte, 0, 2*len(order))
+	type frame struct {
+		n  NI
+		cx int
+	}
+	stack := []frame{{root, 0}}
+	code = append(code, '(')
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.cx == len(ch[top.n]) {
+			code = append(code, ')')
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		c := ch[top.n][top.cx]
+		top.cx++
+		code = append(code, '(')
+		stack = append(stack, frame{c, 0})
+	}
+	return TreeCode(code), ch
+}
+
+// canonMap returns a mapping from nodes of the tree with canonical children
+// ch1 rooted at r1 to nodes of the tree with canonical children ch2 rooted
+// at r2.  The trees must be isomorphic.
+func canonMap(ch1 [][]NI, r1 NI, ch2 [][]NI, r2 NI) []NI {
+	m := make([]NI, len(ch1))
+	for i := range m {
+		m[i] = -1
+	}
+	m[r1] = r2
+	stack := []NI{r1}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for i, c := range ch1[n] {
+			m[c] = ch2[m[n]][i]
+			stack = append(stack, c)
+		}
+	}
+	return m
+}
+
+// TreeCode returns the canonical encoding of the subtree of f rooted at n.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+//
+// See also UnrootedTreeCode.
+func (f FromList) TreeCode(n NI) TreeCode {
+	ch, _ := f.Transpose(nil)
+	c, _ := canonTree(ch.AdjacencyList, n, false)
+	return c
+}
+
+// TreeIsomorphism returns an isomorphism from the subtree of f rooted at r
+// to the subtree of f2 rooted at r2.
+//
+// An isomorphism maps r to r2 and preserves parent-child relationships.
+// The result is a mapping from nodes of f to nodes of f2.  It has length
+// len(f.Paths) and holds -1 for nodes not in the subtree rooted at r.
+// TreeIsomorphism returns nil if the subtrees are not isomorphic.
+//
+// Only the From members of f.Paths and f2.Paths are used.  FromLists f and
+// f2 cannot be cyclic.
+//
+// See also UnrootedTreeIsomorphism.
+func (f FromList) TreeIsomorphism(r NI, f2 FromList, r2 NI) []NI {
+	t1, _ := f.Transpose(nil)
+	t2, _ := f2.Transpose(nil)
+	return t1.TreeIsomorphism(r, t2, r2)
+}
+
+// unrootedCanon returns the canonical encoding of the tree of f containing
+// n, as an unrooted tree.  Also returned are the center used as the root of
+// the encoding and the canonical children.
+func (f FromList) unrootedCanon(n NI) (TreeCode, NI, [][]NI) {
+	u, _ := f.Undirected(nil)
+	c1, c2 := f.Center(n)
+	code, ch := canonTree(u.AdjacencyList, c1, true)
+	if c2 >= 0 {
+		if code2, ch2 := canonTree(u.AdjacencyList, c2, true); code2 < code {
+			return code2, c2, ch2
+		}
+	}
+	return code, c1, ch
+}
+
+// UnrootedTreeCode returns the canonical encoding of the tree of f
+// containing n, considered as an unrooted tree.
+//
+// Arc directions and the root of f are ignored.  The tree is encoded as
+// rooted at its center.  If the tree has two center nodes, the lesser of the
+// two encodings is returned.
+//
+// Only the From members of f.Paths are used.  FromList f cannot be cyclic.
+//
+// See also TreeCode.
+func (f FromList) UnrootedTreeCode(n NI) TreeCode {
+	c, _, _ := f.unrootedCanon(n)
+	return c
+}
+
+// UnrootedTreeIsomorphism returns an isomorphism from the tree of f
+// containing n to the tree of f2 containing n2, considering both as
+// unrooted trees.
+//
+// An isomorphism preserves adjacency regardless of arc direction.
+// The result is a mapping from nodes of f to nodes of f2.  It has length
+// len(f.Paths) and holds -1 for nodes not in the tree containing n.
+// UnrootedTreeIsomorphism returns nil if the trees are not isomorphic.
+//
+// Only the From members of f.Paths and f2.Paths are used.  FromLists f and
+// f2 cannot be cyclic.
+//
+// See also TreeIsomorphism.
+func (f FromList) UnrootedTreeIsomorphism(n NI, f2 FromList, n2 NI) []NI {
+	code1, c1, ch1 := f.unrootedCanon(n)
+	code2, c2, ch2 := f2.unrootedCanon(n2)
+	if code1 != code2 {
+		return nil
+	}
+	return canonMap(ch1, c1, ch2, c2)
+}
+
+// TreeCode returns the canonical encoding of the tree of g rooted at root.
+//
+// Graph g must represent an arborescence from root, as tested for example
+// by IsTree.  Nodes not reachable from root are ignored.
+func (g Directed) TreeCode(root NI) TreeCode {
+	c, _ := canonTree(g.AdjacencyList, root, false)
+	return c
+}
+
+// TreeIsomorphism returns an isomorphism from the tree of g rooted at r to
+// the tree of g2 rooted at r2.
+//
+// Graphs g and g2 must represent arborescences from r and r2 respectively,
+// as tested for example by IsTree.  Nodes not reachable from the roots are
+// ignored.
+//
+// An isomorphism maps r to r2 and preserves arcs.  The result is a mapping
+// from nodes of g to nodes of g2.  It has length g.Order() and holds -1 for
+// nodes not reachable from r.  TreeIsomorphism returns nil if the trees are
+// not isomorphic.
+func (g Directed) TreeIsomorphism(r NI, g2 Directed, r2 NI) []NI {
+	code1, ch1 := canonTree(g.AdjacencyList, r, false)
+	code2, ch2 := canonTree(g2.AdjacencyList, r2, false)
+	if code1 != code2 {
+		return nil
+	}
+	return canonMap(ch1, r, ch2, r2)
+}
@@ -0,0 +1,196 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleFromList_TreeCode() {
+	//   0       3
+	//  / \     / \
+	// 1   2   4   5
+	//     |   |
+	//     6   7
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: -1},
+		4: {From: 3},
+		5: {From: 3},
+		6: {From: 2},
+		7: {From: 4},
+	}}
+	fmt.Println(f.TreeCode(0))
+	fmt.Println(f.TreeCode(3))
+	fmt.Println(f.TreeCode(2))
+	// Output:
+	// (()(()))
+	// (()(()))
+	// (())
+}
+
+func ExampleFromList_TreeIsomorphism() {
+	//   0       3
+	//  / \     / \
+	// 1   2   4   5
+	//     |   |
+	//     6   7
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 0},
+		3: {From: -1},
+		4: {From: 3},
+		5: {From: 3},
+		6: {From: 2},
+		7: {From: 4},
+	}}
+	fmt.Println(f.TreeIsomorphism(0, f, 3))
+	fmt.Println(f.TreeIsomorphism(0, f, 2))
+	// Output:
+	// [3 5 4 -1 -1 -1 7 -1]
+	// []
+}
+
+func ExampleFromList_UnrootedTreeCode() {
+	// 0<--1-->2-->7   3-->4-->5-->6
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: 1},
+		1: {From: -1},
+		2: {From: 1},
+		3: {From: -1},
+		4: {From: 3},
+		5: {From: 4},
+		6: {From: 5},
+		7: {From: 2},
+	}}
+	fmt.Println(f.TreeCode(1), f.TreeCode(3))
+	fmt.Println(f.UnrootedTreeCode(0), f.UnrootedTreeCode(6))
+	fmt.Println(f.UnrootedTreeIsomorphism(0, f, 6))
+	// Output:
+	// (()(())) (((())))
+	// (()(())) (()(()))
+	// [6 5 4 -1 -1 -1 -1 3]
+}
+
+func ExampleTreeCode_Hash() {
+	// deduplicate trees using TreeCode as a map key
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: -1},
+		3: {From: 2},
+		4: {From: -1},
+	}}
+	seen := map[graph.TreeCode][]graph.NI{}
+	for n, e := range f.Paths {
+		if e.From < 0 {
+			c := f.TreeCode(graph.NI(n))
+			seen[c] = append(seen[c], graph.NI(n))
+		}
+	}
+	fmt.Println(seen)
+	c := f.TreeCode(0)
+	fmt.Println(c.Hash() == f.TreeCode(2).Hash())
+	// Output:
+	// map[(()):[0 2] ():[4]]
+	// true
+}
+
+func ExampleDirected_TreeIsomorphism() {
+	//   0        4
+	//  / \       |
+	// 1   2      5
+	//     |     / \
+	//     3    6   7
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		2: {3},
+		4: {5},
+		5: {6, 7},
+		7: {},
+	}}
+	fmt.Println(g.TreeCode(0), g.TreeCode(5))
+	fmt.Println(g.TreeIsomorphism(0, g, 5))
+	// Output:
+	// (()(())) (()())
+	// []
+}
+
+// relabel returns f with nodes randomly renumbered and the permutation used.
+func relabel(f graph.FromList, r *rand.Rand) (graph.FromList, []int) {
+	perm := r.Perm(len(f.Paths))
+	g := graph.NewFromList(len(f.Paths))
+	for n, e := range f.Paths {
+		fr := e.From
+		if fr >= 0 {
+			fr = graph.NI(perm[fr])
+		}
+		g.Paths[perm[n]].From = fr
+	}
+	return g, perm
+}
+
+func TestTreeIsomorphism(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 100; i++ {
+		f := randomForest(1+r.Intn(60), 0, r)
+		root := f.Root(0)
+		g, perm := relabel(f, r)
+		gRoot := graph.NI(perm[root])
+		if f.TreeCode(root) != g.TreeCode(gRoot) {
+			t.Fatal("TreeCode")
+		}
+		m := f.TreeIsomorphism(root, g, gRoot)
+		if m == nil {
+			t.Fatal("TreeIsomorphism nil")
+		}
+		for n, e := range f.Paths {
+			if e.From >= 0 && g.Paths[m[n]].From != m[e.From] {
+				t.Fatal("TreeIsomorphism not an isomorphism")
+			}
+		}
+		// reroot g at a random node for the unrooted test
+		u, _ := g.Undirected(nil)
+		var h graph.FromList
+		u.SpanTree(graph.NI(r.Intn(len(g.Paths))), &h)
+		if f.UnrootedTreeCode(0) != h.UnrootedTreeCode(0) {
+			t.Fatal("UnrootedTreeCode")
+		}
+		m = f.UnrootedTreeIsomorphism(0, h, 0)
+		if m == nil {
+			t.Fatal("UnrootedTreeIsomorphism nil")
+		}
+		for n, e := range f.Paths {
+			if e.From < 0 {
+				continue
+			}
+			a, b := m[n], m[e.From]
+			if h.Paths[a].From != b && h.Paths[b].From != a {
+				t.Fatal("UnrootedTreeIsomorphism not an isomorphism")
+			}
+		}
+		// a single changed arc should usually break isomorphism
+		if len(f.Paths) > 3 {
+			j := graph.NI(r.Intn(len(f.Paths)))
+			if j != root {
+				g2, _ := relabel(f, r)
+				k := f.Paths[j].From
+				c := f.TreeCode(root)
+				f.Paths[j].From = root
+				if ok, _ := f.Cyclic(); !ok && f.TreeCode(root) != c &&
+					f.TreeIsomorphism(root, g2, g2.Root(0)) != nil {
+					t.Fatal("TreeIsomorphism false positive")
+				}
+				f.Paths[j].From = k
+			}
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// triangles.go has triangle counting and clustering coefficients.
+//
+// Triangles are counted on the simple undirected graph underlying a graph,
+// ignoring loops, parallel edges, and arc directions.  Edges are oriented
+// from earlier to later nodes in a degeneracy ordering so that each node
+// has at most k out-neighbors for a graph of degeneracy k.  The ordering is
+// found in linear time by the core decomposition peel of coreNumbers.  Each
+// triangle is then found exactly once in O(mk) total time.
+
+// triHalf is a half arc of an oriented simple graph with a multiplicity,
+// the number of arcs of the original graph between the two nodes in either
+// direction, ignoring parallel arcs.
+type triHalf struct {
+	to NI
+	m  int
+}
+
+// orientSimple returns the simple undirected graph underlying a, oriented
+// by degeneracy ordering.
+//
+// Returned are the oriented adjacency lists, and for each node its number
+// of distinct neighbors and the number of those neighbors with reciprocal
+// arcs.  If directed is false, a is taken to be undirected and
+// multiplicities are all 1.
+func orientSimple(a AdjacencyList, directed bool) (out [][]triHalf, deg, bi []int) {
+	var in AdjacencyList
+	if directed {
+		t, _ := Directed{a}.Transpose()
+		in = t.AdjacencyList
+	}
+	flag := make([]int, len(a)) // bit 1 for an arc out, bit 2 for an arc in
+	s := make(AdjacencyList, len(a))
+	m := make([][]int, len(a))
+	deg = make([]int, len(a))
+	bi = make([]int, len(a))
+	for u, to := range a {
+		var nb []NI
+		add := func(v NI, f int) {
+			if v == NI(u) {
+				return
+			}
+			if flag[v] == 0 {
+				nb = append(nb, v)
+			}
+			flag[v] |= f
+		}
+		for _, v := range to {
+			add(v, 1)
+		}
+		if directed {
+			for _, v := range in[u] {
+				add(v, 2)
+			}
+		}
+		s[u] = nb
+		m[u] = make([]int, len(nb))
+		for x, v := range nb {
+			m[u][x] = 1
+			if flag[v] == 3 {
+				m[u][x] = 2
+				bi[u]++
+			}
+			flag[v] = 0
+		}
+		deg[u] = len(nb)
+	}
+	ord := corePeel(append([]int(nil), deg...), func(n NI, visit func(NI)) {
+		for _, v := range s[n] {
+			visit(v)
+		}
+	})
+	rank := make([]int, len(a))
+	for r, n := range ord {
+		rank[n] = r
+	}
+	out = make([][]triHalf, len(a))
+	for u, nb := range s {
+		for x, v := range nb {
+			if rank[v] > rank[u] {
+				out[u] = append(out[u], triHalf{v, m[u][x]})
+			}
+		}
+	}
+	return
+}
+
+// eachTriangle calls visit for each triangle of an oriented simple graph.
+// Argument m is the product of the multiplicities of the three edges.
+func eachTriangle(out [][]triHalf, visit func(u, v, w NI, m int)) {
+	mark := make([]int, len(out)) // multiplicity of edge from u, or 0
+	for u, ou := range out {
+		for _, h := range ou {
+			mark[h.to] = h.m
+		}
+		for _, h := range ou {
+			for _, h2 := range out[h.to] {
+				if mw := mark[h2.to]; mw > 0 {
+					visit(NI(u), h.to, h2.to, h.m*h2.m*mw)
+				}
+			}
+		}
+		for _, h := range ou {
+			mark[h.to] = 0
+		}
+	}
+}
+
+// Triangles counts triangles in an undirected graph.
+//
+// Loops and parallel edges are ignored.
+//
+// Returned is the number of triangles containing each node and the total
+// number of triangles in g.
+func (g Undirected) Triangles() (perNode []int, total int) {
+	out, _, _ := orientSimple(g.AdjacencyList, false)
+	perNode = make([]int, len(out))
+	eachTriangle(out, func(u, v, w NI, _ int) {
+		perNode[u]++
+		perNode[v]++
+		perNode[w]++
+		total++
+	})
+	return
+}
+
+// LocalClustering computes the local clustering coefficient of each node
+// of an undirected graph.
+//
+// The local clustering coefficient of a node is the fraction of pairs of
+// its neighbors that are themselves neighbors.  Loops and parallel edges
+// are ignored.  The coefficient of a node with fewer than two neighbors is
+// 0.
+func (g Undirected) LocalClustering() []float64 {
+	out, deg, _ := orientSimple(g.AdjacencyList, false)
+	c := make([]float64, len(out))
+	eachTriangle(out, func(u, v, w NI, _ int) {
+		c[u]++
+		c[v]++
+		c[w]++
+	})
+	for n, d := range deg {
+		if d > 1 {
+			c[n] *= 2 / float64(d*(d-1))
+		}
+	}
+	return c
+}
+
+// AverageClustering returns the mean of the local clustering coefficients
+// of the nodes of an undirected graph.
+//
+// Nodes with fewer than two neighbors are included with coefficient 0.
+// AverageClustering of a graph with no nodes is 0.
+//
+// See also LocalClustering and Transitivity.
+func (g Undirected) AverageClustering() float64 {
+	c := g.LocalClustering()
+	if len(c) == 0 {
+		return 0
+	}
+	s := 0.
+	for _, c := range c {
+		s += c
+	}
+	return s / float64(len(c))
+}
+
+// Transitivity returns the global clustering coefficient of an undirected
+// graph.
+//
+// Transitivity is the fraction of connected triples of nodes that are
+// closed into triangles, that is, three times the number of triangles
+// divided by the number of paths of length 2.  Loops and parallel edges are
+// ignored.  Transitivity of a graph with no paths of length 2 is 0.
+//
+// Transitivity weights nodes of high degree more heavily than
+// AverageClustering.
+func (g Undirected) Transitivity() float64 {
+	out, deg, _ := orientSimple(g.AdjacencyList, false)
+	t := 0
+	eachTriangle(out, func(_, _, _ NI, _ int) { t++ })
+	triples := 0
+	for _, d := range deg {
+		triples += d * (d - 1) / 2
+	}
+	if triples == 0 {
+		return 0
+	}
+	return 3 * float64(t) / float64(triples)
+}
+
+// Clustering computes the local clustering coefficient of each node of a
+// directed graph.
+//
+// The coefficient is that of Fagiolo, counting all directed triangles
+// formed by a node and two of its neighbors, without regard to arc
+// directions, and dividing by the number of such triangles possible.  For
+// node n with total degree d (in-degree plus out-degree) and b reciprocal
+// neighbor pairs, this is d(d-1) - 2b.  Loops and parallel arcs are
+// ignored.  The coefficient of a node with no possible triangles is 0.
+//
+// For a directed graph with all arcs reciprocal, the result is the same
+// as that of Undirected.LocalClustering.
+func (g Directed) Clustering() []float64 {
+	out, deg, bi := orientSimple(g.AdjacencyList, true)
+	c := make([]float64, len(out))
+	eachTriangle(out, func(u, v, w NI, m int) {
+		c[u] += float64(m)
+		c[v] += float64(m)
+		c[w] += float64(m)
+	})
+	for n, s := range deg {
+		// deg counts distinct neighbors; total degree counts reciprocal
+		// neighbors twice.
+		d := s + bi[n]
+		if p := d*(d-1) - 2*bi[n]; p > 0 {
+			c[n] /= float64(p)
+		}
+	}
+	return c
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Triangles() {
+	//   0---1
+	//   |\  |
+	//   | \ |
+	//   |  \|
+	//   3---2---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	g.AddEdge(0, 2)
+	g.AddEdge(2, 4)
+	fmt.Println(g.Triangles())
+	fmt.Printf("%.3f\n", g.LocalClustering())
+	fmt.Printf("%.3f\n", g.AverageClustering())
+	fmt.Printf("%.3f\n", g.Transitivity())
+	// Output:
+	// [2 1 2 1 0] 2
+	// [0.667 1.000 0.333 1.000 0.000]
+	// 0.600
+	// 0.545
+}
+
+func ExampleDirected_Clustering() {
+	// 0 --> 1 --> 2
+	//  ^         /
+	//   \-------/
+	//
+	// and 0 <--> 2
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {0},
+	}}
+	fmt.Printf("%.3f\n", g.Clustering())
+	// Output:
+	// [0.500 1.000 0.500]
+}
+
+func TestTriangles(t *testing.T) {
+	r := rand.New(rand.NewSource(41))
+	for i := 0; i < 30; i++ {
+		n := 1 + r.Intn(12)
+		var g graph.Undirected
+		var d graph.Directed
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		d.AdjacencyList = make(graph.AdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			fr, to := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+			g.AddEdge(fr, to)
+			d.AdjacencyList[fr] = append(d.AdjacencyList[fr], to)
+		}
+		// adjacency matrices, ignoring loops and parallels
+		am := make([][]int, n)
+		dm := make([][]int, n)
+		for u := range am {
+			am[u] = make([]int, n)
+			dm[u] = make([]int, n)
+			for _, v := range g.AdjacencyList[u] {
+				if int(v) != u {
+					am[u][v] = 1
+				}
+			}
+			for _, v := range d.AdjacencyList[u] {
+				if int(v) != u {
+					dm[u][v] = 1
+				}
+			}
+		}
+		per, total := g.Triangles()
+		lc := g.LocalClustering()
+		dc := d.Clustering()
+		wantTotal := 0
+		triples := 0
+		for u := 0; u < n; u++ {
+			tu, deg := 0, 0
+			td, dtot, dbi := 0, 0, 0
+			for v := 0; v < n; v++ {
+				deg += am[u][v]
+				dtot += dm[u][v] + dm[v][u]
+				dbi += dm[u][v] * dm[v][u]
+				for w := v + 1; w < n; w++ {
+					tu += am[u][v] * am[u][w] * am[v][w]
+					td += (dm[u][v] + dm[v][u]) * (dm[u][w] + dm[w][u]) *
+						(dm[v][w] + dm[w][v])
+				}
+			}
+			wantTotal += tu
+			triples += deg * (deg - 1) / 2
+			if per[u] != tu {
+				t.Fatal("Triangles", g, u, per[u], tu)
+			}
+			want := 0.
+			if deg > 1 {
+				want = 2 * float64(tu) / float64(deg*(deg-1))
+			}
+			if math.Abs(lc[u]-want) > 1e-12 {
+				t.Fatal("LocalClustering", g, u, lc[u], want)
+			}
+			want = 0
+			if p := dtot*(dtot-1) - 2*dbi; p > 0 {
+				want = float64(td) / float64(p)
+			}
+			if math.Abs(dc[u]-want) > 1e-12 {
+				t.Fatal("Clustering", d, u, dc[u], want)
+			}
+		}
+		if total != wantTotal/3 {
+			t.Fatal("total", g, total, wantTotal/3)
+		}
+		want := 0.
+		if triples > 0 {
+			want = float64(wantTotal) / float64(triples)
+		}
+		if math.Abs(g.Transitivity()-want) > 1e-12 {
+			t.Fatal("Transitivity", g, g.Transitivity(), want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// truss.go has k-truss decomposition.
+//
+// A k-truss of an undirected graph is a maximal subgraph where each edge
+// is in at least k-2 triangles within the subgraph.  A k-truss is
+// contained in a (k-1)-core and so gives a finer measure of cohesive
+// subgroups than k-cores.
+
+// Trussness computes the trussness of each edge of an undirected graph.
+//
+// The trussness of an edge is the largest k such that the edge is in the
+// k-truss of g.  An edge in no triangle has trussness 2.  Parallel edges
+// are treated as a single edge and each has the trussness of that edge.
+// Loops are in no truss and have trussness 0.
+//
+// The algorithm is that of Wang and Cheng, peeling edges in order of
+// increasing support, the number of triangles containing an edge.  Time
+// is O(sum of squared degrees).
+//
+// Returned is the trussness of each edge in a slice parallel to g; that
+// is, truss[n][x] is the trussness of the edge represented by the arc
+// g.AdjacencyList[n][x].  Both arcs representing an edge have the same
+// trussness.  Also returned is the maximum trussness of any edge.
+func (g Undirected) Trussness() (truss [][]int, maxTruss int) {
+	a := g.AdjacencyList
+	// simple graph with edge ids
+	type nbr struct {
+		to NI
+		e  int
+	}
+	s := make([][]nbr, len(a))
+	mark := make([]int, len(a)) // edge id + 1 of edge from current node
+	var ends []Edge             // ends of each edge
+	for u, to := range a {
+		for _, v := range to {
+			if v > NI(u) && mark[v] == 0 {
+				mark[v] = len(ends) + 1
+				s[u] = append(s[u], nbr{v, len(ends)})
+				s[v] = append(s[v], nbr{NI(u), len(ends)})
+				ends = append(ends, Edge{NI(u), v})
+			}
+		}
+		for _, v := range to {
+			mark[v] = 0
+		}
+	}
+	// support of each edge
+	sup := make([]int, len(ends))
+	out, _, _ := orientSimple(a, false)
+	// edge ids of oriented arcs, parallel to out
+	oe := make([][]int, len(out))
+	for u, ou := range out {
+		for _, h := range s[u] {
+			mark[h.to] = h.e + 1
+		}
+		oe[u] = make([]int, len(ou))
+		for x, h := range ou {
+			oe[u][x] = mark[h.to] - 1
+		}
+		for _, h := range s[u] {
+			mark[h.to] = 0
+		}
+	}
+	// triangles as in eachTriangle, with mark holding edge id + 1 of arcs
+	// from u
+	for u, ou := range out {
+		for x, h := range ou {
+			mark[h.to] = oe[u][x] + 1
+		}
+		for x, h := range ou {
+			for y, h2 := range out[h.to] {
+				if m := mark[h2.to]; m > 0 {
+					sup[oe[u][x]]++
+					sup[m-1]++
+					sup[oe[h.to][y]]++
+				}
+			}
+		}
+		for _, h := range ou {
+			mark[h.to] = 0
+		}
+	}
+	// edges bucket sorted by support, as in Batagelj and Zaversnik
+	maxSup := 0
+	for _, x := range sup {
+		if x > maxSup {
+			maxSup = x
+		}
+	}
+	bin := make([]int, maxSup+1) // start of each bucket in sorted
+	for _, x := range sup {
+		bin[x]++
+	}
+	start := 0
+	for x, c := range bin {
+		bin[x] = start
+		start += c
+	}
+	sorted := make([]int, len(ends))
+	pos := make([]int, len(ends))
+	for e, x := range sup {
+		pos[e] = bin[x]
+		sorted[pos[e]] = e
+		bin[x]++
+	}
+	for x := maxSup; x > 0; x-- {
+		bin[x] = bin[x-1]
+	}
+	bin[0] = 0
+	// decrement support of edge f, keeping sorted order
+	dec := func(f int) {
+		x := sup[f]
+		pf, pg := pos[f], bin[x]
+		if f2 := sorted[pg]; f2 != f {
+			sorted[pf], sorted[pg] = f2, f
+			pos[f], pos[f2] = pg, pf
+		}
+		bin[x]++
+		sup[f]--
+	}
+	// peel
+	removed := make([]bool, len(ends))
+	et := make([]int, len(ends)) // trussness by edge id
+	for _, e := range sorted {
+		u, v := ends[e].N1, ends[e].N2
+		for _, h := range s[u] {
+			if !removed[h.e] {
+				mark[h.to] = h.e + 1
+			}
+		}
+		for _, h := range s[v] {
+			if m := mark[h.to]; m > 0 && h.to != u && !removed[h.e] {
+				if f := m - 1; sup[f] > sup[e] {
+					dec(f)
+				}
+				if sup[h.e] > sup[e] {
+					dec(h.e)
+				}
+			}
+		}
+		for _, h := range s[u] {
+			mark[h.to] = 0
+		}
+		removed[e] = true
+		et[e] = sup[e] + 2
+		if et[e] > maxTruss {
+			maxTruss = et[e]
+		}
+	}
+	truss = make([][]int, len(a))
+	for u, to := range a {
+		for _, h := range s[u] {
+			mark[h.to] = et[h.e]
+		}
+		t := make([]int, len(to))
+		for x, v := range to {
+			t[x] = mark[v] // loops remain 0
+		}
+		truss[u] = t
+		for _, h := range s[u] {
+			mark[h.to] = 0
+		}
+	}
+	return
+}
+
+// KTruss constructs the k-truss of an undirected graph.
+//
+// The k-truss is the subgraph of edges with trussness at least k, as
+// computed by Trussness, and the nodes of those edges.  Parallel edges
+// are included as in g.  Nodes with no edges in the k-truss are not
+// included.
+//
+// The subgraph is constructed as a subgraph of g, with the receiver g as
+// its supergraph.  Subgraph nodes are mapped in order of supergraph NIs.
+func (g *Undirected) KTruss(k int) *UndirectedSubgraph {
+	truss, _ := g.Trussness()
+	s := g.InduceList(nil)
+	for u, t := range truss {
+		for _, t := range t {
+			if t >= k {
+				s.AddNode(NI(u))
+				break
+			}
+		}
+	}
+	for u, to := range g.AdjacencyList {
+		for x, v := range to {
+			if v > NI(u) && truss[u][x] >= k {
+				s.AddEdge(NI(u), v)
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,124 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Trussness() {
+	// K4 on nodes 0-3, triangle 3 4 5, and pendant edge 5 6
+	var g graph.Undirected
+	for _, e := range []graph.Edge{
+		{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3},
+		{3, 4}, {3, 5}, {4, 5}, {5, 6},
+	} {
+		g.AddEdge(e.N1, e.N2)
+	}
+	truss, max := g.Trussness()
+	for n, to := range g.AdjacencyList {
+		for x, to := range to {
+			if graph.NI(n) < to {
+				fmt.Printf("%d-%d: %d\n", n, to, truss[n][x])
+			}
+		}
+	}
+	fmt.Println("max:", max)
+	// Output:
+	// 0-1: 4
+	// 0-2: 4
+	// 0-3: 4
+	// 1-2: 4
+	// 1-3: 4
+	// 2-3: 4
+	// 3-4: 3
+	// 3-5: 3
+	// 4-5: 3
+	// 5-6: 2
+	// max: 4
+}
+
+func ExampleUndirected_KTruss() {
+	// K4 on nodes 0-3, triangle 3 4 5, and pendant edge 5 6
+	var g graph.Undirected
+	for _, e := range []graph.Edge{
+		{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3},
+		{3, 4}, {3, 5}, {4, 5}, {5, 6},
+	} {
+		g.AddEdge(e.N1, e.N2)
+	}
+	s := g.KTruss(3)
+	fmt.Println(s.SuperNI)
+	s = g.KTruss(4)
+	fmt.Println(s.SuperNI)
+	// Output:
+	// [0 1 2 3 4 5]
+	// [0 1 2 3]
+}
+
+// trussBF computes trussness by repeatedly deleting edges with too little
+// support for increasing k.  Loops and parallel edges must not be present.
+func trussBF(g graph.Undirected) map[graph.Edge]int {
+	n := g.Order()
+	adj := make([][]bool, n)
+	for i := range adj {
+		adj[i] = make([]bool, n)
+	}
+	for u, to := range g.AdjacencyList {
+		for _, v := range to {
+			adj[u][v] = true
+		}
+	}
+	truss := map[graph.Edge]int{}
+	for k := 3; ; k++ {
+		for changed := true; changed; {
+			changed = false
+			for u := 0; u < n; u++ {
+				for v := u + 1; v < n; v++ {
+					if !adj[u][v] {
+						continue
+					}
+					s := 0
+					for w := 0; w < n; w++ {
+						if adj[u][w] && adj[v][w] {
+							s++
+						}
+					}
+					if s < k-2 {
+						adj[u][v], adj[v][u] = false, false
+						truss[graph.Edge{graph.NI(u), graph.NI(v)}] = k - 1
+						changed = true
+					}
+				}
+			}
+		}
+		if len(truss) == g.Size() {
+			return truss
+		}
+	}
+}
+
+func TestTrussness(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 30; i++ {
+		g := graph.GnmUndirected(5+r.Intn(15), r.Intn(60), r)
+		want := trussBF(g)
+		truss, _ := g.Trussness()
+		for u, to := range g.AdjacencyList {
+			for x, v := range to {
+				e := graph.Edge{graph.NI(u), v}
+				if v < e.N1 {
+					e.N1, e.N2 = v, e.N1
+				}
+				if truss[u][x] != want[e] {
+					t.Fatal(g, e, truss[u][x], want[e])
+				}
+			}
+		}
+	}
+}
@@ -708,6 +708,52 @@ func (g Undirected) DegeneracyOrdering() (ordering []NI, kbreaks []int) {
 	return
 }
 
+// CoreNumbers computes the core number of each node of an undirected graph.
+//
+// The core number of a node is the largest k such that the node is in a
+// k-core, a maximal subgraph where each node has degree at least k within
+// the subgraph.  Degree here is len(g[n]) and so is the same measure used
+// by Degeneracy and DegeneracyOrdering.  The maximum core number is the
+// degeneracy of g.
+//
+// Time is O(n + m) by the algorithm of Batagelj and Zaversnik.
+//
+// See also KCore.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Undirected) CoreNumbers() []int {
+	a := g.AdjacencyList
+	deg := make([]int, len(a))
+	for n, to := range a {
+		deg[n] = len(to)
+	}
+	return coreNumbers(deg, func(n NI, visit func(NI)) {
+		for _, to := range a[n] {
+			visit(to)
+		}
+	})
+}
+
+// KCore constructs the k-core of an undirected graph.
+//
+// The k-core is the subgraph induced by nodes with core number at least k,
+// as computed by CoreNumbers.  It may have multiple connected components.
+//
+// The subgraph is induced on receiver graph g with InduceBits.  Receiver g
+// becomes the supergraph of the induced subgraph.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g *Undirected) KCore(k int) *UndirectedSubgraph {
+	c := g.CoreNumbers()
+	b := bits.New(len(c))
+	for n, cn := range c {
+		if cn >= k {
+			b.SetBit(n, 1)
+		}
+	}
+	return g.InduceBits(b)
+}
+
 // Degree for undirected graphs, returns the degree of a node.
 //
 // The degree of a node in an undirected graph is the number of incident
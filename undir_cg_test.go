@@ -300,6 +300,29 @@ func ExampleLabeledUndirected_ConnectedComponentReps() {
 	// arcSizes: [6 2 0]
 }
 
+func ExampleLabeledUndirected_CoreNumbers() {
+	//   1   ----5
+	//  / \ /   / \
+	// 0---2---4  |
+	//      \   \ /
+	//   3   ----6
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{0, 2}, 0)
+	g.AddEdge(graph.Edge{1, 2}, 0)
+	g.AddEdge(graph.Edge{2, 4}, 0)
+	g.AddEdge(graph.Edge{2, 5}, 0)
+	g.AddEdge(graph.Edge{2, 6}, 0)
+	g.AddEdge(graph.Edge{4, 5}, 0)
+	g.AddEdge(graph.Edge{4, 6}, 0)
+	g.AddEdge(graph.Edge{5, 6}, 0)
+	fmt.Println("node:  0 1 2 3 4 5 6")
+	fmt.Println("core:", g.CoreNumbers())
+	// Output:
+	// node:  0 1 2 3 4 5 6
+	// core: [2 2 3 0 3 3 3]
+}
+
 func ExampleLabeledUndirected_Degeneracy() {
 	//   1   ----5
 	//  / \ /   / \
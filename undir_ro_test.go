@@ -303,6 +303,29 @@ func ExampleUndirected_ConnectedComponentReps() {
 	// arcSizes: [6 2 0]
 }
 
+func ExampleUndirected_CoreNumbers() {
+	//   1   ----5
+	//  / \ /   / \
+	// 0---2---4  |
+	//      \   \ /
+	//   3   ----6
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	g.AddEdge(2, 6)
+	g.AddEdge(4, 5)
+	g.AddEdge(4, 6)
+	g.AddEdge(5, 6)
+	fmt.Println("node:  0 1 2 3 4 5 6")
+	fmt.Println("core:", g.CoreNumbers())
+	// Output:
+	// node:  0 1 2 3 4 5 6
+	// core: [2 2 3 0 3 3 3]
+}
+
 func ExampleUndirected_Degeneracy() {
 	//   1   ----5
 	//  / \ /   / \
@@ -717,6 +740,35 @@ func ExampleUndirected_IsTree() {
 	// false false
 }
 
+func ExampleUndirected_KCore() {
+	//   1   ----5
+	//  / \ /   / \
+	// 0---2---4  |
+	//      \   \ /
+	//   3   ----6
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	g.AddEdge(2, 6)
+	g.AddEdge(4, 5)
+	g.AddEdge(4, 6)
+	g.AddEdge(5, 6)
+	s := g.KCore(3)
+	fmt.Println("supergraph nodes:", s.SuperNI)
+	for n, to := range s.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// supergraph nodes: [2 4 5 6]
+	// 0 [1 2 3]
+	// 1 [0 2 3]
+	// 2 [0 1 3]
+	// 3 [0 1 2]
+}
+
 func ExampleUndirected_Size() {
 	//   0--\
 	//  / \-/
@@ -0,0 +1,187 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// UnionFind is a disjoint set data structure over nodes 0..n-1.
+//
+// It maintains a partition of nodes into sets, or components, supporting
+// union of sets and finding the set containing a node.  Each set is
+// identified by a representative node.
+//
+// A UnionFind constructed with NewUnionFind uses union by rank and path
+// compression and operations take nearly constant amortized time.
+//
+// A UnionFind constructed with NewRollbackUnionFind additionally supports
+// undoing unions, as needed for example by offline dynamic connectivity
+// algorithms.  It uses union by rank but not path compression and so
+// operations take O(log n) time.
+type UnionFind struct {
+	from     []NI // parent, or -1 for a representative
+	rank     []int
+	size     []int // component size, valid for representatives
+	count    int   // number of components
+	rollback bool
+	hist     []ufUnion
+}
+
+// ufUnion records a union for rollback.
+type ufUnion struct {
+	child    NI // representative made a child of another
+	rankIncr bool
+}
+
+// NewUnionFind constructs a UnionFind of n nodes, each in its own set.
+func NewUnionFind(n int) *UnionFind {
+	u := &UnionFind{
+		from:  make([]NI, n),
+		rank:  make([]int, n),
+		size:  make([]int, n),
+		count: n,
+	}
+	for i := range u.from {
+		u.from[i] = -1
+		u.size[i] = 1
+	}
+	return u
+}
+
+// NewRollbackUnionFind constructs a UnionFind of n nodes, each in its own
+// set, that supports Undo and Rollback.
+func NewRollbackUnionFind(n int) *UnionFind {
+	u := NewUnionFind(n)
+	u.rollback = true
+	return u
+}
+
+// Len returns the number of nodes n.
+func (u *UnionFind) Len() int {
+	return len(u.from)
+}
+
+// Count returns the number of sets.
+func (u *UnionFind) Count() int {
+	return u.count
+}
+
+// Size returns the number of nodes in the set containing n.
+func (u *UnionFind) Size(n NI) int {
+	return u.size[u.Find(n)]
+}
+
+// Same returns true if x and y are in the same set.
+func (u *UnionFind) Same(x, y NI) bool {
+	return u.Find(x) == u.Find(y)
+}
+
+// Find returns the representative node of the set containing n.
+func (u *UnionFind) Find(n NI) NI {
+	s := u.from
+	if u.rollback {
+		for s[n] >= 0 {
+			n = s[n]
+		}
+		return n
+	}
+	// fast paths for n == root or from root.
+	// no updates need in these cases.
+	fr := s[n]
+	if fr < 0 { // n is root
+		return n
+	}
+	n, fr = fr, s[fr]
+	if fr < 0 { // n is from root
+		return n
+	}
+	// otherwise updates needed.
+	// two iterative passes (rather than recursion or stack)
+	// pass 1: find root
+	r := fr
+	for {
+		f := s[r]
+		if f < 0 {
+			break
+		}
+		r = f
+	}
+	// pass 2: update froms
+	for {
+		s[n] = r
+		if fr == r {
+			return r
+		}
+		n = fr
+		fr = s[n]
+	}
+}
+
+// Union merges the sets containing x and y.
+//
+// It returns true if disjoint sets were merged, false if x and y were
+// already in the same set.
+func (u *UnionFind) Union(x, y NI) bool {
+	xr := u.Find(x)
+	yr := u.Find(y)
+	if xr == yr {
+		return false
+	}
+	incr := false
+	switch {
+	case u.rank[xr] < u.rank[yr]:
+		xr, yr = yr, xr
+	case u.rank[xr] == u.rank[yr]:
+		u.rank[xr]++
+		incr = true
+	}
+	u.from[yr] = xr
+	u.size[xr] += u.size[yr]
+	u.count--
+	if u.rollback {
+		u.hist = append(u.hist, ufUnion{yr, incr})
+	}
+	return true
+}
+
+// Unions returns the number of unions that can be undone.
+//
+// The result can be passed to Rollback to restore the current state.
+// Unions returns 0 if u was not constructed with NewRollbackUnionFind.
+func (u *UnionFind) Unions() int {
+	return len(u.hist)
+}
+
+// Undo undoes the most recent union that merged sets.
+//
+// It returns false if there is no union to undo.  It panics if u was not
+// constructed with NewRollbackUnionFind.
+func (u *UnionFind) Undo() bool {
+	if !u.rollback {
+		panic("UnionFind.Undo without rollback")
+	}
+	last := len(u.hist) - 1
+	if last < 0 {
+		return false
+	}
+	h := u.hist[last]
+	u.hist = u.hist[:last]
+	r := u.from[h.child]
+	u.from[h.child] = -1
+	u.size[r] -= u.size[h.child]
+	if h.rankIncr {
+		u.rank[r]--
+	}
+	u.count++
+	return true
+}
+
+// Rollback undoes unions until Unions returns t.
+//
+// It panics if u was not constructed with NewRollbackUnionFind.
+func (u *UnionFind) Rollback(t int) {
+	if !u.rollback {
+		panic("UnionFind.Rollback without rollback")
+	}
+	for len(u.hist) > t {
+		u.Undo()
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUnionFind() {
+	u := graph.NewUnionFind(6)
+	u.Union(0, 1)
+	u.Union(2, 3)
+	u.Union(1, 3)
+	fmt.Println(u.Count(), u.Size(0), u.Same(0, 2), u.Same(0, 4))
+	fmt.Println(u.Union(0, 2))
+	// Output:
+	// 3 4 true false
+	// false
+}
+
+func ExampleNewRollbackUnionFind() {
+	u := graph.NewRollbackUnionFind(6)
+	u.Union(0, 1)
+	t := u.Unions()
+	u.Union(2, 3)
+	u.Union(1, 3)
+	fmt.Println(u.Count(), u.Same(0, 2))
+	u.Rollback(t)
+	fmt.Println(u.Count(), u.Same(0, 2), u.Same(0, 1))
+	u.Undo()
+	fmt.Println(u.Count(), u.Same(0, 1))
+	// Output:
+	// 3 true
+	// 5 false true
+	// 6 false
+}
+
+func TestUnionFind(t *testing.T) {
+	r := rand.New(rand.NewSource(31))
+	const n = 50
+	for _, rb := range []bool{false, true} {
+		var u *graph.UnionFind
+		if rb {
+			u = graph.NewRollbackUnionFind(n)
+		} else {
+			u = graph.NewUnionFind(n)
+		}
+		// naive partition as a component label per node, with history
+		comp := make([]int, n)
+		for i := range comp {
+			comp[i] = i
+		}
+		var hist [][]int
+		for i := 0; i < 500; i++ {
+			if rb && r.Intn(4) == 0 && len(hist) > 0 {
+				k := r.Intn(len(hist))
+				u.Rollback(k)
+				comp = hist[k]
+				hist = hist[:k]
+			} else {
+				x, y := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+				merged := comp[x] != comp[y]
+				if u.Union(x, y) != merged {
+					t.Fatal("Union", x, y)
+				}
+				if merged {
+					hist = append(hist, append([]int{}, comp...))
+					cx, cy := comp[x], comp[y]
+					for j, c := range comp {
+						if c == cy {
+							comp[j] = cx
+						}
+					}
+				}
+			}
+			if rb && u.Unions() != len(hist) {
+				t.Fatal("Unions", u.Unions(), len(hist))
+			}
+			size := map[int]int{}
+			for _, c := range comp {
+				size[c]++
+			}
+			if u.Count() != len(size) {
+				t.Fatal("Count", u.Count(), len(size))
+			}
+			for j := 0; j < 10; j++ {
+				x, y := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+				if u.Same(x, y) != (comp[x] == comp[y]) {
+					t.Fatal("Same", x, y)
+				}
+				if u.Size(x) != size[comp[x]] {
+					t.Fatal("Size", x)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/soniakeys/bits"
+)
+
+// wilson.go has Wilson's algorithm for random spanning trees.
+
+// wilsonFinish populates Len, Leaves, and MaxLen of f for the nodes of
+// inTree.  Nodes not in inTree are left with From -1 and Len 0.
+func wilsonFinish(f *FromList, inTree bits.Bits) {
+	p := f.Paths
+	d := depths(p)
+	f.Leaves = bits.New(len(p))
+	f.MaxLen = 0
+	inTree.IterateOnes(func(n int) bool {
+		p[n].Len = d[n] + 1
+		if p[n].Len > f.MaxLen {
+			f.MaxLen = p[n].Len
+		}
+		f.Leaves.SetBit(n, 1)
+		return true
+	})
+	for _, e := range p {
+		if e.From >= 0 {
+			f.Leaves.SetBit(int(e.From), 0)
+		}
+	}
+}
+
+// RandomSpanningTree constructs a uniformly random spanning tree of the
+// connected component containing root.
+//
+// Every spanning tree of the component is equally likely.  The algorithm
+// is Wilson's, which builds the tree from loop-erased random walks.  Expected
+// time is proportional to the mean hitting time of the graph, which is much
+// less than cover time.
+//
+// The tree is returned as a FromList rooted at root with Len, Leaves, and
+// MaxLen populated.  Nodes not in the component have From -1 and Len 0.
+// Also returned is the number of nodes spanned.
+//
+// If Rand rr is nil, the rand package default shared source is used.
+//
+// See also LabeledUndirected.RandomSpanningTree for a weighted version.
+func (g Undirected) RandomSpanningTree(root NI, rr *rand.Rand) (f FromList, nSpanned int) {
+	ri := rand.Intn
+	if rr != nil {
+		ri = rr.Intn
+	}
+	a := g.AdjacencyList
+	f = NewFromList(len(a))
+	p := f.Paths
+	for n := range p {
+		p[n].From = -1
+	}
+	var comp []NI
+	g.BreadthFirst(root, func(n NI) { comp = append(comp, n) })
+	inTree := bits.New(len(a))
+	inTree.SetBit(int(root), 1)
+	next := make([]NI, len(a))
+	for _, u := range comp {
+		// random walk from u until hitting the tree, remembering only the
+		// last exit from each node.  this erases loops.
+		for n := u; inTree.Bit(int(n)) == 0; n = next[n] {
+			to := a[n]
+			next[n] = to[ri(len(to))]
+		}
+		for n := u; inTree.Bit(int(n)) == 0; n = next[n] {
+			inTree.SetBit(int(n), 1)
+			p[n].From = next[n]
+		}
+	}
+	wilsonFinish(&f, inTree)
+	return f, len(comp)
+}
+
+// RandomSpanningTree constructs a weighted random spanning tree of the
+// connected component containing root.
+//
+// The probability of a spanning tree is proportional to the product of
+// its edge weights.  WeightFunc w must return positive weights.  The
+// algorithm is Wilson's, with random walks taking edges with probability
+// proportional to weight.
+//
+// The tree is returned as a FromList rooted at root with Len, Leaves, and
+// MaxLen populated.  Nodes not in the component have From -1 and Len 0.
+// Returned labels are the labels of the edges leading to each node in the
+// tree.  Also returned is the number of nodes spanned.
+//
+// If Rand rr is nil, the rand package default shared source is used.
+//
+// See also Undirected.RandomSpanningTree for an unweighted version.
+func (g LabeledUndirected) RandomSpanningTree(root NI, w WeightFunc, rr *rand.Rand) (f FromList, labels []LI, nSpanned int) {
+	rf := rand.Float64
+	if rr != nil {
+		rf = rr.Float64
+	}
+	a := g.LabeledAdjacencyList
+	f = NewFromList(len(a))
+	labels = make([]LI, len(a))
+	p := f.Paths
+	for n := range p {
+		p[n].From = -1
+	}
+	// cumulative weights of the arcs of each node
+	cum := make([][]float64, len(a))
+	var comp []NI
+	g.BreadthFirst(root, func(n NI) {
+		comp = append(comp, n)
+		c := make([]float64, len(a[n]))
+		s := 0.
+		for i, h := range a[n] {
+			s += w(h.Label)
+			c[i] = s
+		}
+		cum[n] = c
+	})
+	inTree := bits.New(len(a))
+	inTree.SetBit(int(root), 1)
+	next := make([]Half, len(a))
+	for _, u := range comp {
+		for n := u; inTree.Bit(int(n)) == 0; n = next[n].To {
+			c := cum[n]
+			x := rf() * c[len(c)-1]
+			next[n] = a[n][sort.SearchFloat64s(c, x)]
+		}
+		for n := u; inTree.Bit(int(n)) == 0; n = next[n].To {
+			inTree.SetBit(int(n), 1)
+			p[n].From = next[n].To
+			labels[n] = next[n].Label
+		}
+	}
+	wilsonFinish(&f, inTree)
+	return f, labels, len(comp)
+}
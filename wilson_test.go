@@ -0,0 +1,108 @@
+// Copyright 2017 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_RandomSpanningTree() {
+	// 0---1   4
+	// |\  |
+	// | \ |
+	// 3---2
+	g := graph.Undirected{make(graph.AdjacencyList, 5)}
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	g.AddEdge(0, 2)
+	f, n := g.RandomSpanningTree(0, rand.New(rand.NewSource(3)))
+	fmt.Println("spanned:", n)
+	for n, e := range f.Paths {
+		fmt.Println(n, e.From, e.Len)
+	}
+	// Random output:
+	// spanned: 4
+	// 0 -1 1
+	// 1 0 2
+	// 2 1 3
+	// 3 0 2
+	// 4 -1 0
+}
+
+func ExampleLabeledUndirected_RandomSpanningTree() {
+	//     0
+	// (1)/ \(100)
+	//   1---2
+	//   (100)
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 1)
+	g.AddEdge(graph.Edge{0, 2}, 100)
+	g.AddEdge(graph.Edge{1, 2}, 100)
+	w := func(l graph.LI) float64 { return float64(l) }
+	f, labels, _ := g.RandomSpanningTree(0, w, rand.New(rand.NewSource(1)))
+	for n, e := range f.Paths {
+		fmt.Println(n, e.From, labels[n])
+	}
+	// Random output:
+	// 0 -1 0
+	// 1 2 100
+	// 2 0 100
+}
+
+func TestRandomSpanningTree(t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	// K4 has 16 spanning trees, each should be equally likely.
+	var g graph.Undirected
+	for i := graph.NI(0); i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			g.AddEdge(i, j)
+		}
+	}
+	const samples = 16000
+	count := map[[4]graph.NI]int{}
+	for i := 0; i < samples; i++ {
+		f, n := g.RandomSpanningTree(0, r)
+		if n != 4 || f.MaxLen < 2 {
+			t.Fatal("not spanning")
+		}
+		var k [4]graph.NI
+		for n, e := range f.Paths {
+			k[n] = e.From
+		}
+		count[k]++
+	}
+	if len(count) != 16 {
+		t.Fatal("trees found:", len(count))
+	}
+	for k, c := range count {
+		if math.Abs(float64(c)-samples/16) > samples/16*.2 {
+			t.Fatal("not uniform", k, c)
+		}
+	}
+	// weighted triangle.  trees have probability proportional to the
+	// product of edge weights, 2:3:6.
+	var lg graph.LabeledUndirected
+	lg.AddEdge(graph.Edge{0, 1}, 1)
+	lg.AddEdge(graph.Edge{1, 2}, 2)
+	lg.AddEdge(graph.Edge{2, 0}, 3)
+	w := func(l graph.LI) float64 { return float64(l) }
+	missing := map[graph.LI]int{}
+	for i := 0; i < samples; i++ {
+		_, labels, _ := lg.RandomSpanningTree(0, w, r)
+		missing[6-labels[1]-labels[2]]++
+	}
+	for l, want := range map[graph.LI]float64{3: 2, 2: 3, 1: 6} {
+		want *= samples / 11
+		if c := float64(missing[l]); math.Abs(c-want) > want*.1 {
+			t.Fatal("weighted", l, c, want)
+		}
+	}
+}